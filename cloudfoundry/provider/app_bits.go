@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/appbits"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// resourceMatchBatchSize is the number of resources sent per resource match request, the same as the cf CLI uses.
+const resourceMatchBatchSize = 1000
+
+type appBitsResource struct {
+	Checksum    appBitsChecksum `json:"checksum"`
+	SizeInBytes int64           `json:"size_in_bytes"`
+	Path        string          `json:"path"`
+	Mode        string          `json:"mode"`
+}
+
+type appBitsChecksum struct {
+	Value string `json:"value"`
+}
+
+type appBitsResourceList struct {
+	Resources []appBitsResource `json:"resources"`
+}
+
+func toAppBitsResources(resources []appbits.Resource) []appBitsResource {
+	result := make([]appBitsResource, 0, len(resources))
+	for _, res := range resources {
+		result = append(result, appBitsResource{
+			Checksum:    appBitsChecksum{Value: res.SHA1},
+			SizeInBytes: res.Size,
+			Path:        res.Path,
+			Mode:        fmt.Sprintf("%o", res.Mode&fs.ModePerm),
+		})
+	}
+	return result
+}
+
+// matchedPaths returns the paths of the matched resources together with the number of bytes they make up.
+func matchedPaths(matched []appBitsResource) (map[string]bool, int64) {
+	paths := make(map[string]bool, len(matched))
+	var size int64
+	for _, res := range matched {
+		if !paths[res.Path] {
+			size += res.SizeInBytes
+		}
+		paths[res.Path] = true
+	}
+	return paths, size
+}
+
+// matchResources asks the Cloud Controller which of the resources are already in its resource cache.
+func (r *appResource) matchResources(ctx context.Context, resources []appBitsResource) ([]appBitsResource, error) {
+	var matched []appBitsResource
+	for start := 0; start < len(resources); start += resourceMatchBatchSize {
+		end := min(start+resourceMatchBatchSize, len(resources))
+		var resp appBitsResourceList
+		err := cfAPIRequest(ctx, r.cfClient, http.MethodPost, "/v3/resource_matches", appBitsResourceList{Resources: resources[start:end]}, &resp)
+		if err != nil {
+			return nil, err
+		}
+		matched = append(matched, resp.Resources...)
+	}
+	return matched, nil
+}
+
+// uploadPackageBits uploads the zip archive to the package, the matched resources are taken from the resource cache.
+func (r *appResource) uploadPackageBits(ctx context.Context, pkgGUID string, matched []appBitsResource, zipData []byte) error {
+	if matched == nil {
+		matched = []appBitsResource{}
+	}
+	resources, err := json.Marshal(matched)
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("resources", string(resources)); err != nil {
+		return err
+	}
+	part, err := writer.CreateFormFile("bits", "application.zip")
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(zipData); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfClient.ApiURL("/v3/packages/"+pkgGUID+"/upload"), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return doCFAPIRequest(r.cfClient, req, nil)
+}
+
+// uploadMatchedBits uploads the files at the app path to the package, leaving out the files which the Cloud Controller
+// already has in its resource cache. If resource matching or the partial upload fails, the full archive is uploaded.
+func (r *appResource) uploadMatchedBits(ctx context.Context, pkgGUID string, appPath string) error {
+	source, err := appbits.OpenSource(appPath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	files, err := source.Resources()
+	if err != nil {
+		return fmt.Errorf("unable to compute checksums of %s: %w", appPath, err)
+	}
+	resources := toAppBitsResources(files)
+	var totalSize int64
+	for _, res := range resources {
+		totalSize += res.SizeInBytes
+	}
+
+	matched, err := r.matchResources(ctx, resources)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Resource matching failed, uploading all files: %s", err.Error()))
+	} else {
+		paths, matchedSize := matchedPaths(matched)
+		zipData, err := source.Zip(paths)
+		if err != nil {
+			return err
+		}
+		err = r.uploadPackageBits(ctx, pkgGUID, matched, zipData)
+		if err == nil {
+			tflog.Info(ctx, fmt.Sprintf("Uploaded %d of %d files, %d of %d bytes were matched in the resource cache",
+				len(resources)-len(paths), len(resources), matchedSize, totalSize))
+			return nil
+		}
+		tflog.Warn(ctx, fmt.Sprintf("Uploading unmatched files failed, uploading all files: %s", err.Error()))
+	}
+	zipData, err := source.Zip(nil)
+	if err != nil {
+		return err
+	}
+	return r.uploadPackageBits(ctx, pkgGUID, nil, zipData)
+}
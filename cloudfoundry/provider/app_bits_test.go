@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/appbits"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToAppBitsResources(t *testing.T) {
+	resources := toAppBitsResources([]appbits.Resource{
+		{Path: "bin/run", SHA1: "a", Size: 10, Mode: 0755},
+		{Path: "index.js", SHA1: "b", Size: 20, Mode: 0644},
+	})
+	assert.Equal(t, []appBitsResource{
+		{Checksum: appBitsChecksum{Value: "a"}, SizeInBytes: 10, Path: "bin/run", Mode: "755"},
+		{Checksum: appBitsChecksum{Value: "b"}, SizeInBytes: 20, Path: "index.js", Mode: "644"},
+	}, resources)
+}
+
+func TestMatchedPaths(t *testing.T) {
+	paths, size := matchedPaths([]appBitsResource{
+		{Path: "lib/a.jar", SizeInBytes: 100},
+		{Path: "lib/b.jar", SizeInBytes: 200},
+		{Path: "lib/a.jar", SizeInBytes: 100},
+	})
+	assert.Equal(t, map[string]bool{"lib/a.jar": true, "lib/b.jar": true}, paths)
+	assert.Equal(t, int64(300), size)
+
+	paths, size = matchedPaths(nil)
+	assert.Empty(t, paths)
+	assert.Zero(t, size)
+}
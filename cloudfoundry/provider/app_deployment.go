@@ -0,0 +1,718 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	cfv3operation "github.com/cloudfoundry/go-cfclient/v3/operation"
+	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	deploymentStatusActive    = "ACTIVE"
+	deploymentStatusFinalized = "FINALIZED"
+	deploymentReasonDeployed  = "DEPLOYED"
+	deploymentReasonPaused    = "PAUSED"
+	deploymentStrategyCanary  = "canary"
+
+	processInstanceStateRunning = "RUNNING"
+)
+
+// appDeployment represents the subset of the CF v3 deployment resource used by the provider.
+// go-cfclient does not model the canary strategy options yet, hence the deployments API is called directly.
+type appDeployment struct {
+	GUID         string                    `json:"guid"`
+	Strategy     string                    `json:"strategy"`
+	Status       appDeploymentStatus       `json:"status"`
+	Droplet      cfv3resource.Relationship `json:"droplet"`
+	NewProcesses []appDeploymentProcess    `json:"new_processes"`
+}
+
+type appDeploymentStatus struct {
+	Value  string                     `json:"value"`
+	Reason string                     `json:"reason"`
+	Canary *appDeploymentCanaryStatus `json:"canary,omitempty"`
+}
+
+type appDeploymentCanaryStatus struct {
+	Steps struct {
+		Current int `json:"current"`
+		Total   int `json:"total"`
+	} `json:"steps"`
+}
+
+type appDeploymentProcess struct {
+	GUID string `json:"guid"`
+	Type string `json:"type"`
+}
+
+type appDeploymentCreate struct {
+	Droplet       *cfv3resource.Relationship `json:"droplet,omitempty"`
+	Revision      *cfv3resource.Relationship `json:"revision,omitempty"`
+	Strategy      string                     `json:"strategy,omitempty"`
+	Options       *appDeploymentOptions      `json:"options,omitempty"`
+	Relationships appDeploymentRelationships `json:"relationships"`
+}
+
+type appDeploymentOptions struct {
+	Canary *appDeploymentCanaryOptions `json:"canary,omitempty"`
+}
+
+type appDeploymentCanaryOptions struct {
+	Steps []appDeploymentCanaryStep `json:"steps"`
+}
+
+type appDeploymentCanaryStep struct {
+	InstanceWeight int64 `json:"instance_weight"`
+}
+
+type appDeploymentRelationships struct {
+	App cfv3resource.ToOneRelationship `json:"app"`
+}
+
+const appCrashEventType = "audit.app.process.crash"
+
+type appCrashEvents struct {
+	Resources []appCrashEvent `json:"resources"`
+}
+
+type appCrashEvent struct {
+	CreatedAt time.Time `json:"created_at"`
+	Data      struct {
+		Index           int    `json:"index"`
+		ProcessType     string `json:"process_type"`
+		Reason          string `json:"reason"`
+		ExitDescription string `json:"exit_description"`
+	} `json:"data"`
+}
+
+func newAppDeploymentCreate(appGUID string, strategy string) *appDeploymentCreate {
+	return &appDeploymentCreate{
+		Strategy: strategy,
+		Relationships: appDeploymentRelationships{
+			App: cfv3resource.ToOneRelationship{
+				Data: &cfv3resource.Relationship{GUID: appGUID},
+			},
+		},
+	}
+}
+
+// cfAPIRequest sends a request with an optional JSON body to the CF API and decodes the JSON response into out.
+func cfAPIRequest(ctx context.Context, client *cfv3client.Client, method string, urlPath string, body any, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, client.ApiURL(urlPath), reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return doCFAPIRequest(client, req, out)
+}
+
+// doCFAPIRequest sends the request with the authenticated client and decodes the JSON response into out.
+func doCFAPIRequest(client *cfv3client.Client, req *http.Request, out any) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", client.UserAgent())
+	resp, err := client.HTTPAuthClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		// wrap the CF error, so that e.g. cfv3resource.IsResourceNotFoundError works as for go-cfclient calls
+		var cfErrors cfv3resource.CloudFoundryErrors
+		if json.Unmarshal(respBody, &cfErrors) == nil && len(cfErrors.Errors) > 0 {
+			return fmt.Errorf("%s %s failed with status %d: %w", req.Method, req.URL.Path, resp.StatusCode, cfErrors.Errors[0])
+		}
+		return fmt.Errorf("%s %s failed with status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+func (r *appResource) createDeployment(ctx context.Context, create *appDeploymentCreate) (*appDeployment, error) {
+	var deployment appDeployment
+	err := cfAPIRequest(ctx, r.cfClient, http.MethodPost, "/v3/deployments", create, &deployment)
+	if err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+func (r *appResource) getDeployment(ctx context.Context, guid string) (*appDeployment, error) {
+	var deployment appDeployment
+	err := cfAPIRequest(ctx, r.cfClient, http.MethodGet, "/v3/deployments/"+guid, nil, &deployment)
+	if err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+func (r *appResource) deploymentAction(ctx context.Context, guid string, action string) error {
+	return cfAPIRequest(ctx, r.cfClient, http.MethodPost, "/v3/deployments/"+guid+"/actions/"+action, nil, nil)
+}
+
+// findApp looks up the application, and the space it should live in, by the names given in the configuration.
+// The returned app is nil if it does not exist yet.
+func (r *appResource) findApp(ctx context.Context, appType AppType) (*cfv3resource.App, *cfv3resource.Space, error) {
+	org, err := r.cfClient.Organizations.Single(ctx, &cfv3client.OrganizationListOptions{
+		Names: cfv3client.Filter{
+			Values: []string{appType.Org.ValueString()},
+		},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to find org %s: %w", appType.Org.ValueString(), err)
+	}
+	space, err := r.cfClient.Spaces.Single(ctx, &cfv3client.SpaceListOptions{
+		Names: cfv3client.Filter{
+			Values: []string{appType.Space.ValueString()},
+		},
+		OrganizationGUIDs: cfv3client.Filter{
+			Values: []string{org.GUID},
+		},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to find space %s: %w", appType.Space.ValueString(), err)
+	}
+	app, err := r.cfClient.Applications.First(ctx, &cfv3client.AppListOptions{
+		Names: cfv3client.Filter{
+			Values: []string{appType.Name.ValueString()},
+		},
+		SpaceGUIDs: cfv3client.Filter{
+			Values: []string{space.GUID},
+		},
+	})
+	if err != nil {
+		if errors.Is(err, cfv3client.ErrNoResultsReturned) {
+			return nil, space, nil
+		}
+		return nil, nil, err
+	}
+	return app, space, nil
+}
+
+// stagingPollingOptions polls packages, builds and droplets until the deadline of the context, if any.
+func stagingPollingOptions(ctx context.Context) *cfv3client.PollingOptions {
+	return &cfv3client.PollingOptions{
+		Timeout:       remainingTimeout(ctx),
+		CheckInterval: time.Second * 2,
+		FailedState:   "FAILED",
+	}
+}
+
+// applyManifest applies the app manifest to the space without staging or restarting the app.
+func (r *appResource) applyManifest(ctx context.Context, spaceGUID string, appManifestValue *cfv3operation.AppManifest) error {
+	manifest := cfv3operation.Manifest{
+		Version:      "1",
+		Applications: []*cfv3operation.AppManifest{appManifestValue},
+	}
+	raw, err := yaml.Marshal(&manifest)
+	if err != nil {
+		return err
+	}
+	jobID, err := r.cfClient.Manifests.ApplyManifest(ctx, spaceGUID, string(raw))
+	if err != nil {
+		return err
+	}
+	return pollJob(ctx, *r.cfClient, jobID, remainingTimeout(ctx))
+}
+
+// stageDroplet uploads the app bits or docker image reference as a new package and stages it into a droplet
+// without touching the currently running instances. With resource matching the bits are read from the app path
+// instead of bits. It returns the GUID of the staged droplet.
+func (r *appResource) stageDroplet(ctx context.Context, appGUID string, appType AppType, bits io.Reader) (string, error) {
+	var pkgCreate *cfv3resource.PackageCreate
+	if !appType.DockerImage.IsNull() {
+		var username, password string
+		if appType.DockerCredentials != nil {
+			username = appType.DockerCredentials.Username.ValueString()
+			password = appType.DockerCredentials.Password.ValueString()
+		}
+		pkgCreate = cfv3resource.NewDockerPackageCreate(appGUID, appType.DockerImage.ValueString(), username, password)
+	} else {
+		pkgCreate = cfv3resource.NewPackageCreate(appGUID)
+	}
+	pkg, err := r.cfClient.Packages.Create(ctx, pkgCreate)
+	if err != nil {
+		return "", fmt.Errorf("unable to create package: %w", err)
+	}
+	switch {
+	case !appType.DockerImage.IsNull():
+	case appType.ResourceMatching.ValueBool():
+		err = r.uploadMatchedBits(ctx, pkg.GUID, appType.Path.ValueString())
+		if err != nil {
+			return "", fmt.Errorf("unable to upload package bits: %w", err)
+		}
+	default:
+		_, err = r.cfClient.Packages.Upload(ctx, pkg.GUID, bits)
+		if err != nil {
+			return "", fmt.Errorf("unable to upload package bits: %w", err)
+		}
+	}
+	err = r.cfClient.Packages.PollReady(ctx, pkg.GUID, stagingPollingOptions(ctx))
+	if err != nil {
+		return "", fmt.Errorf("package %s did not become ready: %w", pkg.GUID, err)
+	}
+	return stagePackage(ctx, r.cfClient, pkg.GUID)
+}
+
+// stagePackage stages the ready package into a droplet with the lifecycle of its app and returns the GUID of the droplet.
+func stagePackage(ctx context.Context, client *cfv3client.Client, packageGUID string) (string, error) {
+	build, err := client.Builds.Create(ctx, cfv3resource.NewBuildCreate(packageGUID))
+	if err != nil {
+		return "", fmt.Errorf("unable to create build: %w", err)
+	}
+	err = client.Builds.PollStaged(ctx, build.GUID, stagingPollingOptions(ctx))
+	if err != nil {
+		return "", fmt.Errorf("staging of build %s failed: %w", build.GUID, err)
+	}
+	build, err = client.Builds.Get(ctx, build.GUID)
+	if err != nil {
+		return "", err
+	}
+	if build.Droplet == nil {
+		return "", fmt.Errorf("build %s staged without a droplet", build.GUID)
+	}
+	return build.Droplet.GUID, nil
+}
+
+// pushStaged pushes the app without the go-cfclient push operation, which is needed for canary deployments, resource
+// matching and existing droplets. The manifest is applied first, which creates the app if it does not exist yet, then
+// the bits are staged into a droplet, or the configured droplet is used, and the droplet is rolled out according to
+// the strategy, 'blue-green' as a rolling deployment. A canary deployment continues every paused step once its pause
+// has elapsed and its instances are healthy. New apps are simply started.
+func (r *appResource) pushStaged(ctx context.Context, app *cfv3resource.App, spaceGUID string, appType AppType, appManifestValue *cfv3operation.AppManifest, bits io.Reader) (*cfv3resource.App, error) {
+	err := r.applyManifest(ctx, spaceGUID, appManifestValue)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply manifest: %w", err)
+	}
+	existing := app != nil
+	if !existing {
+		app, _, err = r.findApp(ctx, appType)
+		if err != nil {
+			return nil, err
+		}
+		if app == nil {
+			return nil, fmt.Errorf("app %s was not created by the manifest", appType.Name.ValueString())
+		}
+	}
+	var dropletGUID string
+	if !appType.Droplet.IsNull() {
+		dropletGUID, err = r.promoteDroplet(ctx, app.GUID, appType.Droplet.ValueString())
+	} else {
+		dropletGUID, err = r.stageDroplet(ctx, app.GUID, appType, bits)
+	}
+	if err != nil {
+		return nil, err
+	}
+	strategy := appType.Strategy.ValueString()
+	if strategy == "blue-green" {
+		// the deployments API has no blue-green strategy, a rolling deployment replaces the instances without downtime
+		strategy = "rolling"
+	}
+	switch {
+	case appType.Stopped.ValueBool():
+		err = r.setCurrentDroplet(ctx, app.GUID, dropletGUID)
+		if err == nil && app.State == "STARTED" {
+			_, err = r.cfClient.Applications.Stop(ctx, app.GUID)
+		}
+	case existing && (strategy == deploymentStrategyCanary || strategy == "rolling"):
+		err = r.rolloutDroplet(ctx, app.GUID, dropletGUID, strategy, appType)
+	default:
+		err = r.rolloutDroplet(ctx, app.GUID, dropletGUID, "", appType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.cfClient.Applications.Get(ctx, app.GUID)
+}
+
+// rolloutDroplet runs the droplet, or the current droplet if dropletGUID is empty, on the started app. With the
+// 'canary' or 'rolling' strategy a deployment replaces the instances without downtime, otherwise the app is restarted.
+func (r *appResource) rolloutDroplet(ctx context.Context, appGUID string, dropletGUID string, strategy string, appType AppType) error {
+	if strategy != deploymentStrategyCanary && strategy != "rolling" {
+		if dropletGUID != "" {
+			if err := r.setCurrentDroplet(ctx, appGUID, dropletGUID); err != nil {
+				return err
+			}
+		}
+		_, err := r.cfClient.Applications.Restart(ctx, appGUID)
+		return err
+	}
+	create := newAppDeploymentCreate(appGUID, strategy)
+	if dropletGUID != "" {
+		create.Droplet = &cfv3resource.Relationship{GUID: dropletGUID}
+	}
+	if strategy == deploymentStrategyCanary {
+		create.Options = canaryDeploymentOptions(appType.CanarySteps)
+	}
+	deployment, err := r.createDeployment(ctx, create)
+	if err != nil {
+		return fmt.Errorf("unable to create %s deployment: %w", strategy, err)
+	}
+	if strategy == deploymentStrategyCanary {
+		return r.waitForCanaryDeployment(ctx, deployment.GUID, appType)
+	}
+	timeout, checkInterval := deploymentTimeouts(appType)
+	return r.waitForDeployment(ctx, deployment.GUID, timeout, checkInterval)
+}
+
+// deployRevision deploys an existing revision of the app, which restores its droplet, environment and process commands.
+// The configured strategy is honoured, 'canary' deploys the revision in steps while all others roll it out.
+func (r *appResource) deployRevision(ctx context.Context, appGUID string, appType AppType) (*cfv3resource.App, error) {
+	strategy := ""
+	if appType.Strategy.ValueString() == deploymentStrategyCanary {
+		strategy = deploymentStrategyCanary
+	}
+	create := newAppDeploymentCreate(appGUID, strategy)
+	create.Revision = &cfv3resource.Relationship{GUID: appType.Revision.ValueString()}
+	if strategy == deploymentStrategyCanary {
+		create.Options = canaryDeploymentOptions(appType.CanarySteps)
+	}
+	deployment, err := r.createDeployment(ctx, create)
+	if err != nil {
+		return nil, fmt.Errorf("unable to deploy revision %s: %w", appType.Revision.ValueString(), err)
+	}
+	if strategy == deploymentStrategyCanary {
+		err = r.waitForCanaryDeployment(ctx, deployment.GUID, appType)
+	} else {
+		timeout, checkInterval := deploymentTimeouts(appType)
+		err = r.waitForDeployment(ctx, deployment.GUID, timeout, checkInterval)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.cfClient.Applications.Get(ctx, appGUID)
+}
+
+func canaryDeploymentOptions(canarySteps []CanaryStep) *appDeploymentOptions {
+	if len(canarySteps) == 0 {
+		return nil
+	}
+	steps := make([]appDeploymentCanaryStep, 0, len(canarySteps))
+	for _, step := range canarySteps {
+		steps = append(steps, appDeploymentCanaryStep{InstanceWeight: step.InstanceWeight.ValueInt64()})
+	}
+	return &appDeploymentOptions{
+		Canary: &appDeploymentCanaryOptions{Steps: steps},
+	}
+}
+
+// waitForCanaryDeployment drives a canary deployment to completion. The deployment is cancelled
+// if a step fails its health gate or the overall timeout is reached.
+func (r *appResource) waitForCanaryDeployment(ctx context.Context, deploymentGUID string, appType AppType) error {
+	timeout, checkInterval := canaryDeploymentTimeouts(appType)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	step := 0
+	for {
+		deployment, err := r.getDeployment(ctx, deploymentGUID)
+		if err != nil {
+			return r.cancelDeployment(ctx, deploymentGUID, err)
+		}
+		switch deployment.Status.Value {
+		case deploymentStatusFinalized:
+			if deployment.Status.Reason == deploymentReasonDeployed {
+				return nil
+			}
+			return fmt.Errorf("canary deployment %s finished with reason %s", deploymentGUID, deployment.Status.Reason)
+		case deploymentStatusActive:
+			if deployment.Status.Reason != deploymentReasonPaused {
+				break
+			}
+			canaryStep := canaryStepAt(appType.CanarySteps, step)
+			tflog.Info(ctx, fmt.Sprintf("Canary deployment %s paused at step %d", deploymentGUID, step+1))
+			if canaryStep.PauseDuration.ValueInt64() > 0 {
+				err = sleepWithContext(ctx, time.Duration(canaryStep.PauseDuration.ValueInt64())*time.Second)
+				if err != nil {
+					return r.cancelDeployment(ctx, deploymentGUID, err)
+				}
+			}
+			if canaryStep.HealthCheck.IsNull() || canaryStep.HealthCheck.ValueBool() {
+				err = r.checkProcessesHealthy(ctx, deployment.NewProcesses)
+				if err != nil {
+					return r.cancelDeployment(ctx, deploymentGUID, fmt.Errorf("canary step %d failed its health check: %w", step+1, err))
+				}
+			}
+			err = r.deploymentAction(ctx, deploymentGUID, "continue")
+			if err != nil {
+				return r.cancelDeployment(ctx, deploymentGUID, err)
+			}
+			step++
+		}
+		err = sleepWithContext(ctx, checkInterval)
+		if err != nil {
+			return r.cancelDeployment(ctx, deploymentGUID, err)
+		}
+	}
+}
+
+// cancelDeployment cancels the deployment, rolling the app back to its previous droplet, and returns the cause.
+func (r *appResource) cancelDeployment(ctx context.Context, deploymentGUID string, cause error) error {
+	// the original context may already be done, the cancellation must still reach the API
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+	defer cancel()
+	err := r.deploymentAction(cancelCtx, deploymentGUID, "cancel")
+	if err != nil {
+		return fmt.Errorf("%w; cancelling deployment %s failed: %s", cause, deploymentGUID, err.Error())
+	}
+	return fmt.Errorf("%w; deployment %s has been cancelled", cause, deploymentGUID)
+}
+
+// checkProcessesHealthy verifies that every instance of the given processes is running.
+func (r *appResource) checkProcessesHealthy(ctx context.Context, processes []appDeploymentProcess) error {
+	for _, process := range processes {
+		stats, err := r.cfClient.Processes.GetStats(ctx, process.GUID)
+		if err != nil {
+			return err
+		}
+		err = unhealthyInstancesError(process.Type, stats.Stats)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForDeployment waits until the deployment has been finalized and reports an error unless it was deployed. The
+// deployment is cancelled if it does not finish within the timeout, like a canary deployment.
+func (r *appResource) waitForDeployment(ctx context.Context, deploymentGUID string, timeout time.Duration, checkInterval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		deployment, err := r.getDeployment(ctx, deploymentGUID)
+		if err != nil {
+			return r.cancelDeployment(ctx, deploymentGUID, err)
+		}
+		if deployment.Status.Value == deploymentStatusFinalized {
+			if deployment.Status.Reason == deploymentReasonDeployed {
+				return nil
+			}
+			return fmt.Errorf("deployment %s finished with reason %s", deploymentGUID, deployment.Status.Reason)
+		}
+		err = sleepWithContext(ctx, checkInterval)
+		if err != nil {
+			return r.cancelDeployment(ctx, deploymentGUID, fmt.Errorf("deployment %s did not finish within %s: %w", deploymentGUID, timeout, err))
+		}
+	}
+}
+
+// waitForAppHealthy polls the process stats of the app until every desired instance is running.
+func (r *appResource) waitForAppHealthy(ctx context.Context, appGUID string, timeout time.Duration, checkInterval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		healthErr := r.checkAppHealthy(ctx, appGUID)
+		if healthErr == nil {
+			return nil
+		}
+		err := sleepWithContext(ctx, checkInterval)
+		if err != nil {
+			return fmt.Errorf("app did not become healthy within %s: %w", timeout, healthErr)
+		}
+	}
+}
+
+// checkAppHealthy verifies that all instances of all processes of the app are running.
+func (r *appResource) checkAppHealthy(ctx context.Context, appGUID string) error {
+	processes, err := r.cfClient.Processes.ListForAppAll(ctx, appGUID, nil)
+	if err != nil {
+		return err
+	}
+	for _, process := range processes {
+		if process.Instances == 0 {
+			continue
+		}
+		stats, err := r.cfClient.Processes.GetStats(ctx, process.GUID)
+		if err != nil {
+			return err
+		}
+		err = unhealthyInstancesError(process.Type, stats.Stats)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCurrentDroplet makes the droplet the current droplet of the app, it is used on the next start.
+func (r *appResource) setCurrentDroplet(ctx context.Context, appGUID string, dropletGUID string) error {
+	body := cfv3resource.ToOneRelationship{
+		Data: &cfv3resource.Relationship{GUID: dropletGUID},
+	}
+	return cfAPIRequest(ctx, r.cfClient, http.MethodPatch, "/v3/apps/"+appGUID+"/relationships/current_droplet", body, nil)
+}
+
+// rollback restores the droplet which was current before a failed push and waits until the app is healthy again.
+// A running app gets the droplet through a rolling deployment, a stopped one just has its current droplet reset.
+func (r *appResource) rollback(ctx context.Context, appGUID string, dropletGUID string, appType AppType) error {
+	timeout, checkInterval := deploymentTimeouts(appType)
+	app, err := r.cfClient.Applications.Get(ctx, appGUID)
+	if err != nil {
+		return err
+	}
+	if appType.Stopped.ValueBool() {
+		return r.setCurrentDroplet(ctx, appGUID, dropletGUID)
+	}
+	current, err := r.cfClient.Droplets.GetCurrentForApp(ctx, appGUID)
+	if err != nil && !cfv3resource.IsResourceNotFoundError(err) {
+		return err
+	}
+	switch {
+	case current == nil || current.GUID != dropletGUID:
+		tflog.Info(ctx, fmt.Sprintf("Rolling back app %s to droplet %s", appGUID, dropletGUID))
+		create := newAppDeploymentCreate(appGUID, "")
+		create.Droplet = &cfv3resource.Relationship{GUID: dropletGUID}
+		deployment, err := r.createDeployment(ctx, create)
+		if err != nil {
+			return err
+		}
+		err = r.waitForDeployment(ctx, deployment.GUID, timeout, checkInterval)
+		if err != nil {
+			return err
+		}
+	case app.State != "STARTED":
+		_, err = r.cfClient.Applications.Start(ctx, appGUID)
+		if err != nil {
+			return err
+		}
+	}
+	return r.waitForAppHealthy(ctx, appGUID, timeout, checkInterval)
+}
+
+// waitForHealthyInstances waits until all desired instances of the app are running. If they do not get there, the error
+// lists the reasons of the instances which crashed since the push started.
+func (r *appResource) waitForHealthyInstances(ctx context.Context, appGUID string, appType AppType, since time.Time) error {
+	timeout, checkInterval := deploymentTimeouts(appType)
+	err := r.waitForAppHealthy(ctx, appGUID, timeout, checkInterval)
+	if err == nil {
+		return nil
+	}
+	var events appCrashEvents
+	query := url.Values{
+		"types":           []string{appCrashEventType},
+		"target_guids":    []string{appGUID},
+		"created_ats[gt]": []string{since.UTC().Format(time.RFC3339)},
+		"order_by":        []string{"-created_at"},
+	}
+	lookupErr := cfAPIRequest(context.WithoutCancel(ctx), r.cfClient, http.MethodGet, "/v3/audit_events?"+query.Encode(), nil, &events)
+	if lookupErr != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Unable to read crash events of app %s: %s", appGUID, lookupErr.Error()))
+		return err
+	}
+	if reasons := crashReasons(events.Resources); len(reasons) > 0 {
+		return fmt.Errorf("%w\n%s", err, strings.Join(reasons, "\n"))
+	}
+	return err
+}
+
+// crashReasons describes the latest crash of every instance, the events are expected newest first.
+func crashReasons(events []appCrashEvent) []string {
+	seen := map[string]bool{}
+	var latest []appCrashEvent
+	for _, event := range events {
+		if event.Data.ProcessType == "" {
+			event.Data.ProcessType = "web"
+		}
+		key := fmt.Sprintf("%s/%d", event.Data.ProcessType, event.Data.Index)
+		if !seen[key] {
+			seen[key] = true
+			latest = append(latest, event)
+		}
+	}
+	sort.Slice(latest, func(i, j int) bool {
+		if latest[i].Data.ProcessType != latest[j].Data.ProcessType {
+			return latest[i].Data.ProcessType < latest[j].Data.ProcessType
+		}
+		return latest[i].Data.Index < latest[j].Data.Index
+	})
+	reasons := make([]string, 0, len(latest))
+	for _, event := range latest {
+		reasons = append(reasons, fmt.Sprintf("instance %d of process %s crashed at %s: %s (%s)",
+			event.Data.Index, event.Data.ProcessType, event.CreatedAt.UTC().Format(time.RFC3339), event.Data.ExitDescription, event.Data.Reason))
+	}
+	return reasons
+}
+
+// unhealthyInstancesError returns an error listing the instances which are not running, or nil if all are.
+func unhealthyInstancesError(processType string, stats []cfv3resource.ProcessStat) error {
+	var unhealthy []string
+	for _, stat := range stats {
+		if stat.State != processInstanceStateRunning {
+			unhealthy = append(unhealthy, fmt.Sprintf("instance %d is %s", stat.Index, stat.State))
+		}
+	}
+	if len(unhealthy) == 0 {
+		return nil
+	}
+	return fmt.Errorf("process %s is not healthy: %s", processType, strings.Join(unhealthy, ", "))
+}
+
+// canaryStepAt returns the configured step, or an empty step with the default behaviour if there is none.
+func canaryStepAt(steps []CanaryStep, index int) CanaryStep {
+	if index < len(steps) {
+		return steps[index]
+	}
+	return CanaryStep{}
+}
+
+// deploymentTimeouts returns the time to wait for a deployment of the app to finish and the interval between status checks.
+func deploymentTimeouts(appType AppType) (time.Duration, time.Duration) {
+	timeout := defaultTimeout
+	if !appType.AppDeployedRunningTimeout.IsNull() {
+		timeout = time.Duration(appType.AppDeployedRunningTimeout.ValueInt64()) * time.Minute
+	}
+	checkInterval := time.Duration(AppDeployedRunningCheckIntervalSecondsDefault) * time.Second
+	if !appType.AppDeployedRunningCheckInterval.IsNull() {
+		checkInterval = time.Duration(appType.AppDeployedRunningCheckInterval.ValueInt64()) * time.Second
+	}
+	return timeout, checkInterval
+}
+
+// canaryDeploymentTimeouts returns the overall timeout of a canary deployment, which is extended by
+// the configured pauses, and the interval between status checks.
+func canaryDeploymentTimeouts(appType AppType) (time.Duration, time.Duration) {
+	timeout, checkInterval := deploymentTimeouts(appType)
+	for _, step := range appType.CanarySteps {
+		timeout += time.Duration(step.PauseDuration.ValueInt64()) * time.Second
+	}
+	return timeout, checkInterval
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	dropletStateStaged = "STAGED"
+	dropletStateFailed = "FAILED"
+)
+
+// appDroplet represents the subset of the CF v3 droplet resource used by the provider.
+// go-cfclient does not model copying droplets, hence the droplets API is called directly.
+type appDroplet struct {
+	GUID         string            `json:"guid"`
+	State        string            `json:"state"`
+	Error        *string           `json:"error"`
+	Stack        *string           `json:"stack"`
+	Image        *string           `json:"image"`
+	ProcessTypes map[string]string `json:"process_types"`
+	Checksum     *struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"checksum"`
+	Buildpacks []struct {
+		Name          string `json:"name"`
+		BuildpackName string `json:"buildpack_name"`
+		Version       string `json:"version"`
+	} `json:"buildpacks"`
+	Relationships struct {
+		App cfv3resource.ToOneRelationship `json:"app"`
+	} `json:"relationships"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type appDropletCopy struct {
+	Relationships struct {
+		App cfv3resource.ToOneRelationship `json:"app"`
+	} `json:"relationships"`
+}
+
+// appGUID returns the GUID of the app the droplet belongs to.
+func (d *appDroplet) appGUID() string {
+	if d.Relationships.App.Data == nil {
+		return ""
+	}
+	return d.Relationships.App.Data.GUID
+}
+
+func getDroplet(ctx context.Context, client *cfv3client.Client, dropletGUID string) (*appDroplet, error) {
+	var droplet appDroplet
+	err := cfAPIRequest(ctx, client, http.MethodGet, "/v3/droplets/"+dropletGUID, nil, &droplet)
+	if err != nil {
+		return nil, err
+	}
+	return &droplet, nil
+}
+
+// copyDroplet copies the droplet to the app, which may be in another space, and waits until the copy is staged.
+// The copy runs the exact same bits as the source droplet without staging again.
+func copyDroplet(ctx context.Context, client *cfv3client.Client, sourceGUID string, appGUID string) (*appDroplet, error) {
+	var create appDropletCopy
+	create.Relationships.App.Data = &cfv3resource.Relationship{GUID: appGUID}
+	var droplet appDroplet
+	query := url.Values{"source_guid": {sourceGUID}}
+	err := cfAPIRequest(ctx, client, http.MethodPost, "/v3/droplets?"+query.Encode(), create, &droplet)
+	if err != nil {
+		return nil, fmt.Errorf("unable to copy droplet %s to app %s: %w", sourceGUID, appGUID, err)
+	}
+	return waitForDroplet(ctx, client, droplet.GUID)
+}
+
+// waitForDroplet polls the droplet until it is no longer being copied.
+func waitForDroplet(ctx context.Context, client *cfv3client.Client, dropletGUID string) (*appDroplet, error) {
+	options := stagingPollingOptions(ctx)
+	ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+	for {
+		droplet, err := getDroplet(ctx, client, dropletGUID)
+		if err != nil {
+			return nil, err
+		}
+		switch droplet.State {
+		case dropletStateStaged:
+			return droplet, nil
+		case dropletStateFailed:
+			reason := "unknown error"
+			if droplet.Error != nil {
+				reason = *droplet.Error
+			}
+			return nil, fmt.Errorf("droplet %s failed: %s", dropletGUID, reason)
+		}
+		if err := sleepWithContext(ctx, options.CheckInterval); err != nil {
+			return nil, fmt.Errorf("droplet %s is still %s: %w", dropletGUID, droplet.State, err)
+		}
+	}
+}
+
+// promoteDroplet returns the GUID of a staged droplet of the app with the bits of the given droplet. A droplet of
+// another app is copied to the app first.
+func (r *appResource) promoteDroplet(ctx context.Context, appGUID string, dropletGUID string) (string, error) {
+	droplet, err := getDroplet(ctx, r.cfClient, dropletGUID)
+	if err != nil {
+		return "", fmt.Errorf("unable to read droplet %s: %w", dropletGUID, err)
+	}
+	if droplet.appGUID() == appGUID {
+		if droplet.State != dropletStateStaged {
+			return "", fmt.Errorf("droplet %s is %s and cannot be deployed", dropletGUID, droplet.State)
+		}
+		return droplet.GUID, nil
+	}
+	tflog.Info(ctx, fmt.Sprintf("Copying droplet %s of app %s to app %s", dropletGUID, droplet.appGUID(), appGUID))
+	droplet, err = copyDroplet(ctx, r.cfClient, dropletGUID, appGUID)
+	if err != nil {
+		return "", err
+	}
+	return droplet.GUID, nil
+}
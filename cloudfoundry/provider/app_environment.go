@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// redactedEnvironmentValue replaces the values of sensitive environment variables in the data sources.
+const redactedEnvironmentValue = "(sensitive)"
+
+// Follow-up actions of environment_update_action once the environment variables of a started app changed.
+const (
+	environmentUpdateRestart = "restart"
+	environmentUpdateRestage = "restage"
+	environmentUpdateNone    = "none"
+)
+
+// environmentDigests returns a SHA-256 digest of the value of each environment variable, by name, so that sensitive
+// values can be compared without being kept around or logged.
+func environmentDigests(env map[string]string) map[string]string {
+	digests := make(map[string]string, len(env))
+	for key, value := range env {
+		sum := sha256.Sum256([]byte(value))
+		digests[key] = hex.EncodeToString(sum[:])
+	}
+	return digests
+}
+
+// driftedEnvironmentKeys returns the names of the environment variables which were added, removed or changed in
+// actual compared to expected, sorted.
+func driftedEnvironmentKeys(expected map[string]string, actual map[string]string) []string {
+	expectedDigests, actualDigests := environmentDigests(expected), environmentDigests(actual)
+	var drifted []string
+	for key, digest := range actualDigests {
+		if expectedDigests[key] != digest {
+			drifted = append(drifted, key)
+		}
+	}
+	for key := range expectedDigests {
+		if _, ok := actualDigests[key]; !ok {
+			drifted = append(drifted, key)
+		}
+	}
+	sort.Strings(drifted)
+	return drifted
+}
+
+// mapEnvironmentValuesToType splits the environment of the app into the plain environment and the sensitive
+// environment, the variables configured in sensitive_environment are sensitive. The configured sensitive environment
+// is kept unless the digests of the actual values differ, only the names of drifted variables are logged.
+func mapEnvironmentValuesToType(ctx context.Context, env map[string]string, configuredSensitive types.Map) (types.Map, types.Map, diag.Diagnostics) {
+	var diags, tempDiags diag.Diagnostics
+	sensitiveKeys := map[string]string{}
+	if !configuredSensitive.IsNull() && !configuredSensitive.IsUnknown() {
+		diags.Append(configuredSensitive.ElementsAs(ctx, &sensitiveKeys, false)...)
+	}
+
+	plain, sensitive := map[string]string{}, map[string]string{}
+	for key, value := range env {
+		if _, ok := sensitiveKeys[key]; ok {
+			sensitive[key] = value
+		} else {
+			plain[key] = value
+		}
+	}
+
+	environment := types.MapNull(types.StringType)
+	if len(plain) > 0 {
+		environment, tempDiags = types.MapValueFrom(ctx, types.StringType, plain)
+		diags.Append(tempDiags...)
+	}
+
+	if configuredSensitive.IsNull() || configuredSensitive.IsUnknown() {
+		return environment, configuredSensitive, diags
+	}
+	drifted := driftedEnvironmentKeys(sensitiveKeys, sensitive)
+	if len(drifted) == 0 {
+		return environment, configuredSensitive, diags
+	}
+	tflog.Warn(ctx, "Sensitive environment variables of the app differ from the configuration", map[string]interface{}{"keys": drifted})
+	sensitiveEnvironment := types.MapNull(types.StringType)
+	if len(sensitive) > 0 {
+		sensitiveEnvironment, tempDiags = types.MapValueFrom(ctx, types.StringType, sensitive)
+		diags.Append(tempDiags...)
+	}
+	return environment, sensitiveEnvironment, diags
+}
+
+// redactEnvironment replaces the values of the given environment variables, so that the data sources do not expose
+// the sensitive environment of an app.
+func redactEnvironment(env map[string]string, keys []string) map[string]string {
+	if len(keys) == 0 {
+		return env
+	}
+	redacted := make(map[string]string, len(env))
+	for key, value := range env {
+		redacted[key] = value
+	}
+	for _, key := range keys {
+		if _, ok := redacted[key]; ok {
+			redacted[key] = redactedEnvironmentValue
+		}
+	}
+	return redacted
+}
+
+// environmentChanged reports whether the plain or the sensitive environment differs between the states.
+func environmentChanged(previous AppType, desired AppType) bool {
+	return !previous.Environment.Equal(desired.Environment) || !previous.SensitiveEnvironment.Equal(desired.SensitiveEnvironment)
+}
+
+// restartStrategy returns the deployment strategy used to restart the app, e.g. to apply changed environment variables
+// or sidecars. Apps using 'rolling' or 'blue-green' get a rolling deployment and 'canary' apps a canary deployment, so
+// they pick up the changes without downtime. All others are restarted.
+func restartStrategy(appType AppType) string {
+	switch appType.Strategy.ValueString() {
+	case "rolling", "blue-green":
+		return "rolling"
+	case deploymentStrategyCanary:
+		return deploymentStrategyCanary
+	}
+	return ""
+}
+
+// environmentUpdateRestarts reports whether the environment_update_action restarts or restages the app once its
+// environment variables changed, which is never the case for a stopped app.
+func environmentUpdateRestarts(appType AppType) bool {
+	action := appType.EnvironmentUpdateAction.ValueString()
+	return (action == environmentUpdateRestart || action == environmentUpdateRestage) && !appType.Stopped.ValueBool()
+}
+
+// environmentUpdateMessage describes the follow-up action which applies changed environment variables, empty if none.
+func environmentUpdateMessage(appType AppType) string {
+	if !environmentUpdateRestarts(appType) {
+		return ""
+	}
+	verb := "restarted"
+	if appType.EnvironmentUpdateAction.ValueString() == environmentUpdateRestage {
+		verb = "restaged"
+	}
+	if strategy := restartStrategy(appType); strategy != "" {
+		return fmt.Sprintf("The environment variables of app %s change, it will be %s with a %s deployment after the update.", appType.Name.ValueString(), verb, strategy)
+	}
+	return fmt.Sprintf("The environment variables of app %s change, it will be %s after the update.", appType.Name.ValueString(), verb)
+}
+
+type appPackageList struct {
+	Resources []struct {
+		GUID string `json:"guid"`
+	} `json:"resources"`
+}
+
+// currentPackage returns the GUID of the most recent ready package of the app.
+func (r *appResource) currentPackage(ctx context.Context, appGUID string) (string, error) {
+	query := url.Values{
+		"app_guids": {appGUID},
+		"states":    {"READY"},
+		"order_by":  {"-created_at"},
+		"per_page":  {"1"},
+	}
+	var packages appPackageList
+	err := cfAPIRequest(ctx, r.cfClient, http.MethodGet, "/v3/packages?"+query.Encode(), nil, &packages)
+	if err != nil {
+		return "", err
+	}
+	if len(packages.Resources) == 0 {
+		return "", fmt.Errorf("app %s has no ready package to restage", appGUID)
+	}
+	return packages.Resources[0].GUID, nil
+}
+
+// applyEnvironmentUpdate runs the configured environment_update_action once the environment variables of the app
+// changed. A restage stages the current package again, so that the buildpacks see the new environment as well.
+func (r *appResource) applyEnvironmentUpdate(ctx context.Context, appGUID string, appType AppType) error {
+	if !environmentUpdateRestarts(appType) {
+		return nil
+	}
+	action := appType.EnvironmentUpdateAction.ValueString()
+	strategy := restartStrategy(appType)
+	tflog.Info(ctx, "Applying changed environment variables", map[string]interface{}{"app": appGUID, "action": action, "strategy": strategy})
+	var dropletGUID string
+	if action == environmentUpdateRestage {
+		packageGUID, err := r.currentPackage(ctx, appGUID)
+		if err != nil {
+			return err
+		}
+		dropletGUID, err = stagePackage(ctx, r.cfClient, packageGUID)
+		if err != nil {
+			return err
+		}
+	}
+	return r.rolloutDroplet(ctx, appGUID, dropletGUID, strategy, appType)
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriftedEnvironmentKeys(t *testing.T) {
+	expected := map[string]string{"DB_PASSWORD": "secret", "API_KEY": "key"}
+
+	assert.Empty(t, driftedEnvironmentKeys(expected, map[string]string{"API_KEY": "key", "DB_PASSWORD": "secret"}))
+	assert.Equal(t, []string{"API_KEY", "DB_PASSWORD", "TOKEN"}, driftedEnvironmentKeys(expected, map[string]string{
+		"DB_PASSWORD": "changed",
+		"TOKEN":       "new",
+	}))
+}
+
+func TestMapEnvironmentValuesToType(t *testing.T) {
+	ctx := context.Background()
+	env := map[string]string{"LOG_LEVEL": "debug", "DB_PASSWORD": "secret"}
+
+	t.Run("without sensitive environment", func(t *testing.T) {
+		environment, sensitive, diags := mapEnvironmentValuesToType(ctx, env, types.MapNull(types.StringType))
+		assert.False(t, diags.HasError())
+		assert.Len(t, environment.Elements(), 2)
+		assert.True(t, sensitive.IsNull())
+	})
+
+	t.Run("configured sensitive environment is kept", func(t *testing.T) {
+		configured, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"DB_PASSWORD": "secret"})
+		environment, sensitive, diags := mapEnvironmentValuesToType(ctx, env, configured)
+		assert.False(t, diags.HasError())
+		expected, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"LOG_LEVEL": "debug"})
+		assert.True(t, environment.Equal(expected))
+		assert.True(t, sensitive.Equal(configured))
+	})
+
+	t.Run("drifted sensitive environment is refreshed", func(t *testing.T) {
+		configured, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"DB_PASSWORD": "old", "API_KEY": "key"})
+		environment, sensitive, diags := mapEnvironmentValuesToType(ctx, env, configured)
+		assert.False(t, diags.HasError())
+		assert.Len(t, environment.Elements(), 1)
+		expected, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"DB_PASSWORD": "secret"})
+		assert.True(t, sensitive.Equal(expected))
+	})
+}
+
+func TestSetEnvForUpdate(t *testing.T) {
+	ctx := context.Background()
+	mapOf := func(values map[string]string) types.Map {
+		m, _ := types.MapValueFrom(ctx, types.StringType, values)
+		return m
+	}
+
+	envs, diags := setEnvForUpdate(ctx,
+		mapOf(map[string]string{"LOG_LEVEL": "info", "REMOVED": "x"}),
+		mapOf(map[string]string{"DB_PASSWORD": "old", "OLD_SECRET": "y"}),
+		mapOf(map[string]string{"LOG_LEVEL": "debug"}),
+		mapOf(map[string]string{"DB_PASSWORD": "new"}),
+	)
+	assert.False(t, diags.HasError())
+	assert.Len(t, envs, 4)
+	assert.Equal(t, "debug", *envs["LOG_LEVEL"])
+	assert.Equal(t, "new", *envs["DB_PASSWORD"])
+	assert.Nil(t, envs["REMOVED"])
+	assert.Nil(t, envs["OLD_SECRET"])
+}
+
+func TestRedactEnvironment(t *testing.T) {
+	env := map[string]string{"LOG_LEVEL": "debug", "DB_PASSWORD": "secret"}
+
+	assert.Equal(t, env, redactEnvironment(env, nil))
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "DB_PASSWORD": redactedEnvironmentValue}, redactEnvironment(env, []string{"DB_PASSWORD", "MISSING"}))
+	assert.Equal(t, "secret", env["DB_PASSWORD"])
+}
+
+func TestRestartStrategy(t *testing.T) {
+	for strategy, expected := range map[string]string{
+		"":           "",
+		"none":       "",
+		"rolling":    "rolling",
+		"blue-green": "rolling",
+		"canary":     "canary",
+	} {
+		assert.Equal(t, expected, restartStrategy(AppType{Strategy: types.StringValue(strategy)}), strategy)
+	}
+}
+
+func TestEnvironmentUpdateMessage(t *testing.T) {
+	appType := AppType{
+		Name:                    types.StringValue("my-app"),
+		Strategy:                types.StringValue("blue-green"),
+		EnvironmentUpdateAction: types.StringValue(environmentUpdateRestage),
+	}
+	assert.Equal(t, "The environment variables of app my-app change, it will be restaged with a rolling deployment after the update.", environmentUpdateMessage(appType))
+
+	appType.Strategy = types.StringNull()
+	appType.EnvironmentUpdateAction = types.StringValue(environmentUpdateRestart)
+	assert.Equal(t, "The environment variables of app my-app change, it will be restarted after the update.", environmentUpdateMessage(appType))
+
+	appType.Stopped = types.BoolValue(true)
+	assert.Empty(t, environmentUpdateMessage(appType))
+
+	appType.Stopped = types.BoolValue(false)
+	appType.EnvironmentUpdateAction = types.StringNull()
+	assert.Empty(t, environmentUpdateMessage(appType))
+}
+
+func TestEnvironmentChanged(t *testing.T) {
+	ctx := context.Background()
+	env, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"LOG_LEVEL": "debug"})
+	secret, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"DB_PASSWORD": "secret"})
+	previous := AppType{Environment: env, SensitiveEnvironment: types.MapNull(types.StringType)}
+
+	assert.False(t, environmentChanged(previous, previous))
+	assert.True(t, environmentChanged(previous, AppType{Environment: env, SensitiveEnvironment: secret}))
+	assert.True(t, environmentChanged(previous, AppType{Environment: types.MapNull(types.StringType), SensitiveEnvironment: types.MapNull(types.StringType)}))
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	fileBasedVcapServicesFeature = "file-based-vcap-services"
+	revisionsFeature             = "revisions"
+	serviceBindingK8sFeature     = "service-binding-k8s"
+)
+
+// managedAppFeatures are the app features which are exposed by the data sources, ssh is exposed with enable_ssh.
+var managedAppFeatures = []string{fileBasedVcapServicesFeature, revisionsFeature, serviceBindingK8sFeature}
+
+// configurableAppFeatures are the app features which can be set with the features attribute, ssh is set with
+// enable_ssh and file-based-vcap-services with file_based_vcap_services.
+var configurableAppFeatures = []string{revisionsFeature, serviceBindingK8sFeature}
+
+type appFeature struct {
+	Name    string `json:"name,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+type appFeatureList struct {
+	Resources []appFeature `json:"resources"`
+}
+
+// listAppFeatures returns whether each feature of the app is enabled, by feature name.
+func listAppFeatures(ctx context.Context, client *cfv3client.Client, appGUID string) (map[string]bool, error) {
+	var features appFeatureList
+	err := cfAPIRequest(ctx, client, http.MethodGet, "/v3/apps/"+appGUID+"/features", nil, &features)
+	if err != nil {
+		return nil, err
+	}
+	enabled := make(map[string]bool, len(features.Resources))
+	for _, feature := range features.Resources {
+		enabled[feature.Name] = feature.Enabled
+	}
+	return enabled, nil
+}
+
+func setAppFeature(ctx context.Context, client *cfv3client.Client, appGUID string, name string, enabled bool) error {
+	return cfAPIRequest(ctx, client, http.MethodPatch, "/v3/apps/"+appGUID+"/features/"+name, appFeature{Enabled: enabled}, nil)
+}
+
+// desiredAppFeatures returns the configured app features, including the file-based-vcap-services feature
+// configured with file_based_vcap_services.
+func desiredAppFeatures(ctx context.Context, appType AppType) (map[string]bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	desired := map[string]bool{}
+	if !appType.Features.IsNull() && !appType.Features.IsUnknown() {
+		diags = appType.Features.ElementsAs(ctx, &desired, false)
+	}
+	if !appType.FileBasedVcapServices.IsNull() && !appType.FileBasedVcapServices.IsUnknown() {
+		desired[fileBasedVcapServicesFeature] = appType.FileBasedVcapServices.ValueBool()
+	}
+	return desired, diags
+}
+
+// changedAppFeatures returns the names of the desired features whose state differs from the current one, sorted.
+func changedAppFeatures(current map[string]bool, desired map[string]bool) []string {
+	var changed []string
+	for name, enabled := range desired {
+		if current[name] != enabled {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// updateAppFeatures sets the desired app features which differ from the current ones. It reports whether a
+// feature changed which only takes effect once the app is restarted.
+func (r *appResource) updateAppFeatures(ctx context.Context, appGUID string, desired map[string]bool) (bool, error) {
+	if len(desired) == 0 {
+		return false, nil
+	}
+	current, err := listAppFeatures(ctx, r.cfClient, appGUID)
+	if err != nil {
+		return false, err
+	}
+	restart := false
+	for _, name := range changedAppFeatures(current, desired) {
+		tflog.Info(ctx, "Setting app feature", map[string]interface{}{"app": appGUID, "feature": name, "enabled": desired[name]})
+		if err := setAppFeature(ctx, r.cfClient, appGUID, name, desired[name]); err != nil {
+			return false, err
+		}
+		if name != revisionsFeature {
+			restart = true
+		}
+	}
+	return restart, nil
+}
+
+// readAppFeatures refreshes the configured app features of the state, features which are not configured are not read.
+func (r *appResource) readAppFeatures(ctx context.Context, appType *AppType) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if appType.Features.IsNull() && appType.FileBasedVcapServices.IsNull() {
+		return diags
+	}
+	current, err := listAppFeatures(ctx, r.cfClient, appType.ID.ValueString())
+	if err != nil {
+		diags.AddError("Error reading app features", err.Error())
+		return diags
+	}
+	if !appType.FileBasedVcapServices.IsNull() {
+		appType.FileBasedVcapServices = types.BoolValue(current[fileBasedVcapServicesFeature])
+	}
+	if !appType.Features.IsNull() {
+		configured := map[string]bool{}
+		diags.Append(appType.Features.ElementsAs(ctx, &configured, false)...)
+		for name := range configured {
+			configured[name] = current[name]
+		}
+		var tempDiags diag.Diagnostics
+		appType.Features, tempDiags = types.MapValueFrom(ctx, types.BoolType, configured)
+		diags.Append(tempDiags...)
+	}
+	return diags
+}
+
+// managedAppFeaturesValue returns the managed features of the app as a map value for the data sources.
+func managedAppFeaturesValue(ctx context.Context, current map[string]bool) (types.Map, diag.Diagnostics) {
+	features := make(map[string]bool, len(managedAppFeatures))
+	for _, name := range managedAppFeatures {
+		if enabled, ok := current[name]; ok {
+			features[name] = enabled
+		}
+	}
+	return types.MapValueFrom(ctx, types.BoolType, features)
+}
+
+// readManagedAppFeatures reads the managed features of the app for the data sources if include_features is set,
+// otherwise the features are null and no request is sent.
+func readManagedAppFeatures(ctx context.Context, client *cfv3client.Client, appGUID string, include types.Bool) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !include.ValueBool() {
+		return types.MapNull(types.BoolType), diags
+	}
+	current, err := listAppFeatures(ctx, client, appGUID)
+	if err != nil {
+		diags.AddError("Error reading app features", err.Error())
+		return types.MapNull(types.BoolType), diags
+	}
+	return managedAppFeaturesValue(ctx, current)
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDesiredAppFeatures(t *testing.T) {
+	ctx := context.Background()
+
+	desired, diags := desiredAppFeatures(ctx, AppType{
+		Features:              types.MapNull(types.BoolType),
+		FileBasedVcapServices: types.BoolNull(),
+	})
+	assert.False(t, diags.HasError())
+	assert.Empty(t, desired)
+
+	features, _ := types.MapValueFrom(ctx, types.BoolType, map[string]bool{revisionsFeature: false})
+	desired, diags = desiredAppFeatures(ctx, AppType{
+		Features:              features,
+		FileBasedVcapServices: types.BoolValue(true),
+	})
+	assert.False(t, diags.HasError())
+	assert.Equal(t, map[string]bool{revisionsFeature: false, fileBasedVcapServicesFeature: true}, desired)
+}
+
+func TestChangedAppFeatures(t *testing.T) {
+	current := map[string]bool{"ssh": true, revisionsFeature: true, fileBasedVcapServicesFeature: false}
+
+	assert.Empty(t, changedAppFeatures(current, map[string]bool{revisionsFeature: true}))
+	assert.Equal(t,
+		[]string{fileBasedVcapServicesFeature, revisionsFeature, serviceBindingK8sFeature},
+		changedAppFeatures(current, map[string]bool{
+			revisionsFeature:             false,
+			fileBasedVcapServicesFeature: true,
+			serviceBindingK8sFeature:     true,
+		}),
+	)
+}
+
+func TestManagedAppFeaturesValue(t *testing.T) {
+	ctx := context.Background()
+	features, diags := managedAppFeaturesValue(ctx, map[string]bool{"ssh": true, revisionsFeature: true})
+	assert.False(t, diags.HasError())
+	expected, _ := types.MapValueFrom(ctx, types.BoolType, map[string]bool{revisionsFeature: true})
+	assert.True(t, features.Equal(expected))
+}
+
+func TestReadManagedAppFeaturesNotIncluded(t *testing.T) {
+	features, diags := readManagedAppFeatures(context.Background(), nil, "app-guid", types.BoolNull())
+	assert.False(t, diags.HasError())
+	assert.True(t, features.IsNull())
+}
@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	logclient "code.cloudfoundry.org/go-log-cache/v3"
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultLogTailLines = 100
+	logPollInterval     = 2 * time.Second
+	// logReadLimit is the maximum number of envelopes log-cache returns per read.
+	logReadLimit = 1000
+)
+
+type appLogLine struct {
+	Timestamp  int64
+	SourceType string
+	Instance   string
+	Stream     string
+	Payload    string
+}
+
+// String formats the log line like the cf CLI does, e.g. '2024-05-01T10:00:00.00Z [APP/PROC/WEB/0] OUT Started'.
+func (l appLogLine) String() string {
+	source := l.SourceType
+	if l.Instance != "" {
+		source += "/" + l.Instance
+	}
+	timestamp := time.Unix(0, l.Timestamp).UTC().Format("2006-01-02T15:04:05.00Z")
+	return fmt.Sprintf("%s [%s] %s %s", timestamp, source, l.Stream, strings.TrimRight(l.Payload, "\n"))
+}
+
+// matchesSourceType reports whether the source type starts with one of the filters, all source types match without filters.
+func matchesSourceType(sourceType string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(filters, func(filter string) bool {
+		return strings.HasPrefix(sourceType, filter)
+	})
+}
+
+// logTail keeps the last lines added to it.
+type logTail struct {
+	lines []string
+	size  int
+}
+
+func newLogTail(size int) *logTail {
+	return &logTail{size: size}
+}
+
+func (t *logTail) add(line string) {
+	if t.size <= 0 {
+		return
+	}
+	if len(t.lines) == t.size {
+		t.lines = t.lines[1:]
+	}
+	t.lines = append(t.lines, line)
+}
+
+// logCacheURL returns the override if set, or derives the log-cache URL from the API URL by replacing its 'api.' host prefix.
+func logCacheURL(apiURL string, override string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+	parsedURL, err := url.Parse(apiURL)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(parsedURL.Host, "api.") {
+		return "", fmt.Errorf("unable to derive the log-cache URL from %s, the host does not start with 'api.'", apiURL)
+	}
+	return "https://" + strings.Replace(parsedURL.Host, "api.", "log-cache.", 1), nil
+}
+
+// appLogStreamer polls log-cache for the logs of an app in the background while it is pushed.
+type appLogStreamer struct {
+	r           *appResource
+	appType     AppType
+	appGUID     string
+	client      *logclient.Client
+	sourceTypes []string
+	stream      bool
+	tail        *logTail
+	start       time.Time
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// startAppLogStreamer starts tailing the logs of the app from the start time on. For a new app the GUID is empty and
+// the app is looked up by name until the push has created it. It returns nil if log-cache cannot be used.
+func (r *appResource) startAppLogStreamer(ctx context.Context, appType AppType, appGUID string, start time.Time) *appLogStreamer {
+	logging := appType.Logging
+	addr, err := logCacheURL(r.cfClient.ApiURL("/"), logging.LogCacheURL.ValueString())
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Not tailing app logs: %s", err.Error()))
+		return nil
+	}
+	var sourceTypes []string
+	if !logging.SourceTypes.IsNull() {
+		logging.SourceTypes.ElementsAs(ctx, &sourceTypes, false)
+	}
+	tailLines := defaultLogTailLines
+	if !logging.TailLines.IsNull() {
+		tailLines = int(logging.TailLines.ValueInt64())
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	s := &appLogStreamer{
+		r:           r,
+		appType:     appType,
+		appGUID:     appGUID,
+		client:      logclient.NewClient(addr, logclient.WithHTTPClient(r.cfClient.HTTPAuthClient())),
+		sourceTypes: sourceTypes,
+		stream:      logging.Stream.IsNull() || logging.Stream.ValueBool(),
+		tail:        newLogTail(tailLines),
+		start:       start,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go s.run(streamCtx)
+	return s
+}
+
+func (s *appLogStreamer) run(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.poll(ctx)
+		}
+	}
+}
+
+func (s *appLogStreamer) poll(ctx context.Context) {
+	if s.appGUID == "" {
+		app, _, err := s.r.findApp(ctx, s.appType)
+		if err != nil || app == nil {
+			return
+		}
+		s.appGUID = app.GUID
+	}
+	start, err := readLogs(ctx, s.client, s.appGUID, s.start, s.sourceTypes, func(line string) {
+		s.tail.add(line)
+		if s.stream {
+			tflog.Info(ctx, line, map[string]interface{}{"app": s.appType.Name.ValueString()})
+		}
+	})
+	s.start = start
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Reading app logs failed: %s", err.Error()))
+	}
+}
+
+// readLogs reads the logs of the source from the start time on and calls add with each log line of the source types.
+// It returns the time to continue reading from.
+func readLogs(ctx context.Context, client *logclient.Client, sourceID string, start time.Time, sourceTypes []string, add func(line string)) (time.Time, error) {
+	for {
+		envelopes, err := client.Read(ctx, sourceID, start, logclient.WithLimit(logReadLimit))
+		if err != nil {
+			return start, err
+		}
+		for _, e := range envelopes {
+			if ts := time.Unix(0, e.GetTimestamp()); !ts.Before(start) {
+				start = ts.Add(time.Nanosecond)
+			}
+			log := e.GetLog()
+			if log == nil || !matchesSourceType(e.GetTags()["source_type"], sourceTypes) {
+				continue
+			}
+			add(appLogLine{
+				Timestamp:  e.GetTimestamp(),
+				SourceType: e.GetTags()["source_type"],
+				Instance:   e.GetInstanceId(),
+				Stream:     log.GetType().String(),
+				Payload:    string(log.GetPayload()),
+			}.String())
+		}
+		if len(envelopes) < logReadLimit {
+			return start, nil
+		}
+	}
+}
+
+// stop ends the background polling, reads the remaining logs and returns the last log lines.
+func (s *appLogStreamer) stop(ctx context.Context) []string {
+	s.cancel()
+	<-s.done
+	s.poll(ctx)
+	return s.tail.lines
+}
+
+// recentLogsQuery selects the envelopes read by readRecentEnvelopes.
+type recentLogsQuery struct {
+	start         time.Time
+	end           time.Time
+	envelopeTypes []logcache_v1.EnvelopeType
+	sourceTypes   []string
+	limit         int
+}
+
+// readRecentEnvelopes reads the most recent envelopes of the source in the time window, newest first, until the limit
+// of envelopes of the source types is reached.
+func readRecentEnvelopes(ctx context.Context, client *logclient.Client, sourceID string, query recentLogsQuery) ([]*loggregator_v2.Envelope, error) {
+	options := []logclient.ReadOption{logclient.WithDescending(), logclient.WithLimit(logReadLimit)}
+	if len(query.envelopeTypes) > 0 {
+		options = append(options, logclient.WithEnvelopeTypes(query.envelopeTypes...))
+	}
+	var envelopes []*loggregator_v2.Envelope
+	end := query.end
+	for len(envelopes) < query.limit {
+		batch, err := client.Read(ctx, sourceID, query.start, append(options, logclient.WithEndTime(end))...)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range batch {
+			if len(envelopes) == query.limit {
+				break
+			}
+			if matchesSourceType(e.GetTags()["source_type"], query.sourceTypes) {
+				envelopes = append(envelopes, e)
+			}
+		}
+		if len(batch) < logReadLimit {
+			break
+		}
+		end = time.Unix(0, batch[len(batch)-1].GetTimestamp()-1)
+	}
+	return envelopes, nil
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppLogLineString(t *testing.T) {
+	line := appLogLine{
+		Timestamp:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC).UnixNano(),
+		SourceType: "APP/PROC/WEB",
+		Instance:   "0",
+		Stream:     "OUT",
+		Payload:    "Started\n",
+	}
+	assert.Equal(t, "2024-05-01T10:00:00.00Z [APP/PROC/WEB/0] OUT Started", line.String())
+
+	line.SourceType, line.Instance, line.Stream = "STG", "", "ERR"
+	assert.Equal(t, "2024-05-01T10:00:00.00Z [STG] ERR Started", line.String())
+}
+
+func TestMatchesSourceType(t *testing.T) {
+	assert.True(t, matchesSourceType("APP/PROC/WEB", nil))
+	assert.True(t, matchesSourceType("APP/PROC/WEB", []string{"STG", "APP"}))
+	assert.True(t, matchesSourceType("STG", []string{"STG"}))
+	assert.False(t, matchesSourceType("RTR", []string{"STG", "APP"}))
+}
+
+func TestLogTail(t *testing.T) {
+	tail := newLogTail(2)
+	tail.add("a")
+	tail.add("b")
+	tail.add("c")
+	assert.Equal(t, []string{"b", "c"}, tail.lines)
+
+	tail = newLogTail(0)
+	tail.add("a")
+	assert.Empty(t, tail.lines)
+}
+
+func TestLogCacheURL(t *testing.T) {
+	addr, err := logCacheURL("https://api.cf.example.com/v3/apps/1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://log-cache.cf.example.com", addr)
+
+	addr, err = logCacheURL("https://api.cf.example.com", "https://logs.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://logs.example.com", addr)
+
+	_, err = logCacheURL("https://cf.example.com", "")
+	assert.Error(t, err)
+}
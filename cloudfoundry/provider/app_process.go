@@ -0,0 +1,286 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	cfv3operation "github.com/cloudfoundry/go-cfclient/v3/operation"
+	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The scale and health check settings of an app and its processes, which are left to cloudfoundry_app_process if
+// ignore_process_drift is set on the app.
+var (
+	processStringSettings = []string{
+		"memory", "disk_quota", "log_rate_limit_per_second", "health_check_type", "health_check_http_endpoint",
+		"readiness_health_check_type", "readiness_health_check_http_endpoint",
+	}
+	processInt64Settings = []string{
+		"instances", "health_check_invocation_timeout", "health_check_interval", "timeout",
+		"readiness_health_check_invocation_timeout", "readiness_health_check_interval",
+	}
+)
+
+// appProcess represents the subset of the CF v3 process resource used by the provider.
+// The processes API is called directly, as go-cfclient does not model the readiness health check.
+type appProcess struct {
+	GUID                         string                  `json:"guid"`
+	Type                         string                  `json:"type"`
+	Instances                    int                     `json:"instances"`
+	MemoryInMB                   int                     `json:"memory_in_mb"`
+	DiskInMB                     int                     `json:"disk_in_mb"`
+	LogRateLimitInBytesPerSecond int                     `json:"log_rate_limit_in_bytes_per_second"`
+	HealthCheck                  appProcessHealthCheck   `json:"health_check"`
+	ReadinessHealthCheck         appProcessHealthCheck   `json:"readiness_health_check"`
+	Relationships                appProcessRelationships `json:"relationships"`
+	CreatedAt                    time.Time               `json:"created_at"`
+	UpdatedAt                    time.Time               `json:"updated_at"`
+}
+
+type appProcessRelationships struct {
+	App cfv3resource.ToOneRelationship `json:"app"`
+}
+
+type appProcessHealthCheck struct {
+	Type string                    `json:"type,omitempty"`
+	Data appProcessHealthCheckData `json:"data"`
+}
+
+type appProcessHealthCheckData struct {
+	Timeout           *int    `json:"timeout,omitempty"`
+	InvocationTimeout *int    `json:"invocation_timeout,omitempty"`
+	Interval          *int    `json:"interval,omitempty"`
+	Endpoint          *string `json:"endpoint,omitempty"`
+}
+
+type appProcessUpdate struct {
+	HealthCheck          *appProcessHealthCheck `json:"health_check,omitempty"`
+	ReadinessHealthCheck *appProcessHealthCheck `json:"readiness_health_check,omitempty"`
+}
+
+type appProcessScale struct {
+	Instances                    *int `json:"instances,omitempty"`
+	MemoryInMB                   *int `json:"memory_in_mb,omitempty"`
+	DiskInMB                     *int `json:"disk_in_mb,omitempty"`
+	LogRateLimitInBytesPerSecond *int `json:"log_rate_limit_in_bytes_per_second,omitempty"`
+}
+
+// appGUID returns the GUID of the app the process belongs to.
+func (p *appProcess) appGUID() string {
+	if p.Relationships.App.Data == nil {
+		return ""
+	}
+	return p.Relationships.App.Data.GUID
+}
+
+func getProcess(ctx context.Context, client *cfv3client.Client, processGUID string) (*appProcess, error) {
+	var process appProcess
+	err := cfAPIRequest(ctx, client, http.MethodGet, "/v3/processes/"+processGUID, nil, &process)
+	if err != nil {
+		return nil, err
+	}
+	return &process, nil
+}
+
+func getAppProcess(ctx context.Context, client *cfv3client.Client, appGUID string, processType string) (*appProcess, error) {
+	var process appProcess
+	err := cfAPIRequest(ctx, client, http.MethodGet, "/v3/apps/"+appGUID+"/processes/"+processType, nil, &process)
+	if err != nil {
+		return nil, err
+	}
+	return &process, nil
+}
+
+func updateProcess(ctx context.Context, client *cfv3client.Client, processGUID string, update *appProcessUpdate) (*appProcess, error) {
+	var process appProcess
+	err := cfAPIRequest(ctx, client, http.MethodPatch, "/v3/processes/"+processGUID, update, &process)
+	if err != nil {
+		return nil, err
+	}
+	return &process, nil
+}
+
+// scaleProcess scales the process, changing the memory, disk or log rate limit restarts its instances.
+func scaleProcess(ctx context.Context, client *cfv3client.Client, processGUID string, scale *appProcessScale) (*appProcess, error) {
+	var process appProcess
+	err := cfAPIRequest(ctx, client, http.MethodPost, "/v3/processes/"+processGUID+"/actions/scale", scale, &process)
+	if err != nil {
+		return nil, err
+	}
+	return &process, nil
+}
+
+// keepCurrentInstances sets the instances of the manifest to the current instances of the processes of the app, unless
+// the configured instances changed, so that pushing the app does not revert scaling done outside of it, e.g. by an autoscaler.
+func (r *appResource) keepCurrentInstances(ctx context.Context, appGUID string, manifest *cfv3operation.AppManifest, previous *AppType, desired *AppType) error {
+	processes, err := r.cfClient.Processes.ListForAppAll(ctx, appGUID, nil)
+	if err != nil {
+		return err
+	}
+	instances := make(map[string]int, len(processes))
+	for _, process := range processes {
+		instances[process.Type] = process.Instances
+	}
+	if current, ok := instances["web"]; ok && !instancesChanged(previous.Instances, desired.Instances) {
+		manifest.Instances = uinttouintptr(uint(current))
+	}
+	if manifest.Processes == nil {
+		return nil
+	}
+	previousInstances := make(map[string]types.Int64, len(previous.Processes))
+	for _, process := range previous.Processes {
+		previousInstances[process.Type.ValueString()] = process.Instances
+	}
+	desiredInstances := make(map[string]types.Int64, len(desired.Processes))
+	for _, process := range desired.Processes {
+		desiredInstances[process.Type.ValueString()] = process.Instances
+	}
+	for i, process := range *manifest.Processes {
+		processType := string(process.Type)
+		if current, ok := instances[processType]; ok && !instancesChanged(previousInstances[processType], desiredInstances[processType]) {
+			(*manifest.Processes)[i].Instances = uinttouintptr(uint(current))
+		}
+	}
+	return nil
+}
+
+// instancesChanged reports whether the configured instances differ from the previous ones.
+func instancesChanged(previous types.Int64, desired types.Int64) bool {
+	return !desired.IsNull() && !desired.IsUnknown() && !desired.Equal(previous)
+}
+
+// keepConfiguredInstances keeps the configured instances of the app and its processes in the state, instead of the
+// current instances read from Cloud Foundry, if drift of the instances is ignored.
+func (target *AppType) keepConfiguredInstances(source *AppType) {
+	if !source.IgnoreInstancesDrift.ValueBool() {
+		return
+	}
+	if !source.Instances.IsNull() && !source.Instances.IsUnknown() {
+		target.Instances = source.Instances
+	}
+	configured := make(map[string]Process, len(source.Processes))
+	for _, process := range source.Processes {
+		configured[process.Type.ValueString()] = process
+	}
+	for i, process := range target.Processes {
+		if c, ok := configured[process.Type.ValueString()]; ok && !c.Instances.IsNull() && !c.Instances.IsUnknown() {
+			target.Processes[i].Instances = c.Instances
+		}
+	}
+}
+
+// validateUnmanagedProcessSettings reports the scale and health check settings configured on the app or its processes,
+// which must be left to cloudfoundry_app_process if drift of the process settings is ignored.
+func validateUnmanagedProcessSettings(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+	report := func(attributePath path.Path) {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid attribute combination",
+			"the scale and health checks of the processes can not be configured when ignore_process_drift is set, configure them with cloudfoundry_app_process instead",
+		)
+	}
+	for _, name := range processStringSettings {
+		var value types.String
+		diags.Append(config.GetAttribute(ctx, path.Root(name), &value)...)
+		if !value.IsNull() {
+			report(path.Root(name))
+		}
+	}
+	for _, name := range processInt64Settings {
+		var value types.Int64
+		diags.Append(config.GetAttribute(ctx, path.Root(name), &value)...)
+		if !value.IsNull() {
+			report(path.Root(name))
+		}
+	}
+	var processes types.Set
+	diags.Append(config.GetAttribute(ctx, path.Root("processes"), &processes)...)
+	if processes.IsNull() || processes.IsUnknown() {
+		return diags
+	}
+	for _, element := range processes.Elements() {
+		process, ok := element.(types.Object)
+		if !ok || process.IsUnknown() {
+			continue
+		}
+		attributes := process.Attributes()
+		for _, name := range append(append([]string{}, processStringSettings...), processInt64Settings...) {
+			if value, ok := attributes[name]; ok && !value.IsNull() {
+				report(path.Root("processes").AtSetValue(process).AtName(name))
+			}
+		}
+	}
+	return diags
+}
+
+// leaveProcessSettings removes the scale and health checks of the app and its processes from the manifest if drift of
+// the process settings is ignored, so that pushing the app does not revert the settings of cloudfoundry_app_process.
+func (appType *AppType) leaveProcessSettings(manifest *cfv3operation.AppManifest) {
+	if !appType.IgnoreProcessDrift.ValueBool() {
+		return
+	}
+	manifest.Instances = nil
+	manifest.Memory = ""
+	manifest.DiskQuota = ""
+	manifest.LogRateLimitPerSecond = ""
+	manifest.HealthCheckType = ""
+	manifest.HealthCheckHTTPEndpoint = ""
+	manifest.HealthCheckInvocationTimeout = 0
+	manifest.HealthCheckInterval = 0
+	manifest.Timeout = 0
+	manifest.ReadinessHealthCheckType = ""
+	manifest.ReadinessHealthCheckHttpEndpoint = ""
+	manifest.ReadinessHealthInvocationTimeout = 0
+	manifest.ReadinessHealthCheckInterval = 0
+	if manifest.Processes == nil {
+		return
+	}
+	for i := range *manifest.Processes {
+		process := &(*manifest.Processes)[i]
+		process.Instances = nil
+		process.Memory = ""
+		process.DiskQuota = ""
+		process.LogRateLimitPerSecond = ""
+		process.HealthCheckType = ""
+		process.HealthCheckHTTPEndpoint = ""
+		process.HealthCheckInvocationTimeout = 0
+		process.HealthCheckInterval = 0
+		process.Timeout = 0
+		process.ReadinessHealthCheckType = ""
+		process.ReadinessHealthCheckHttpEndpoint = ""
+		process.ReadinessHealthInvocationTimeout = 0
+		process.ReadinessHealthCheckInterval = 0
+	}
+}
+
+// keepUnmanagedProcessSettings keeps the health checks of the app and its processes out of the state if drift of the
+// process settings is ignored, as they are not configured on the app. The computed settings are still read, they are
+// not shown as drift while they are not configured.
+func (target *AppType) keepUnmanagedProcessSettings(source *AppType) {
+	if !source.IgnoreProcessDrift.ValueBool() {
+		return
+	}
+	target.HealthCheckHttpEndpoint = types.StringNull()
+	target.HealthCheckInvocationTimeout = types.Int64Null()
+	target.HealthCheckInterval = types.Int64Null()
+	target.Timeout = types.Int64Null()
+	target.ReadinessHealthCheckHttpEndpoint = types.StringNull()
+	target.ReadinessHealthCheckInvocationTimeout = types.Int64Null()
+	target.ReadinessHealthCheckInterval = types.Int64Null()
+	for i := range target.Processes {
+		target.Processes[i].HealthCheckHttpEndpoint = types.StringNull()
+		target.Processes[i].HealthCheckInvocationTimeout = types.Int64Null()
+		target.Processes[i].HealthCheckInterval = types.Int64Null()
+		target.Processes[i].Timeout = types.Int64Null()
+		target.Processes[i].ReadinessHealthCheckHttpEndpoint = types.StringNull()
+		target.Processes[i].ReadinessHealthCheckInvocationTimeout = types.Int64Null()
+		target.Processes[i].ReadinessHealthCheckInterval = types.Int64Null()
+	}
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// obsoleteServiceBindings returns the previous bindings which are not part of the desired bindings: the bindings to
+// service instances which are no longer desired, and the bindings whose name or parameters changed, as those can only
+// be changed by binding again.
+func obsoleteServiceBindings(previous []ServiceBinding, desired []ServiceBinding) (removed []ServiceBinding, changed []ServiceBinding) {
+	for _, prev := range previous {
+		found, keep := false, false
+		for _, want := range desired {
+			if prev.ServiceInstance.Equal(want.ServiceInstance) {
+				found = true
+				keep = prev.Name.Equal(want.Name) && (want.Params.IsUnknown() || sameServiceBindingParams(prev.Params, want.Params))
+				break
+			}
+		}
+		switch {
+		case !found:
+			removed = append(removed, prev)
+		case !keep:
+			changed = append(changed, prev)
+		}
+	}
+	return removed, changed
+}
+
+// sameServiceBindingParams compares the parameters semantically, so formatting changes do not cause a new binding.
+func sameServiceBindingParams(a jsontypes.Normalized, b jsontypes.Normalized) bool {
+	if a.IsNull() || a.IsUnknown() || b.IsNull() || b.IsUnknown() {
+		return a.Equal(b)
+	}
+	equal, diags := a.StringSemanticEquals(context.Background(), b)
+	return equal && !diags.HasError()
+}
+
+// unbindServices deletes the bindings of the app to the service instances of the given bindings.
+func (r *appResource) unbindServices(ctx context.Context, appGUID string, bindings []ServiceBinding) error {
+	for _, binding := range bindings {
+		opts := cfv3client.NewServiceCredentialBindingListOptions()
+		opts.AppGUIDs = cfv3client.Filter{
+			Values: []string{appGUID},
+		}
+		opts.ServiceInstanceNames = cfv3client.Filter{
+			Values: []string{binding.ServiceInstance.ValueString()},
+		}
+		existing, err := r.cfClient.ServiceCredentialBindings.ListAll(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("unable to look up the binding to service instance %s: %w", binding.ServiceInstance.ValueString(), err)
+		}
+		for _, b := range existing {
+			tflog.Info(ctx, fmt.Sprintf("Deleting binding %s of app %s to service instance %s", b.GUID, appGUID, binding.ServiceInstance.ValueString()))
+			jobID, err := r.cfClient.ServiceCredentialBindings.Delete(ctx, b.GUID)
+			if err != nil {
+				return fmt.Errorf("unable to delete the binding to service instance %s: %w", binding.ServiceInstance.ValueString(), err)
+			}
+			if jobID != "" {
+				if err := pollJob(ctx, *r.cfClient, jobID, remainingTimeout(ctx)); err != nil {
+					return fmt.Errorf("unable to delete the binding to service instance %s: %w", binding.ServiceInstance.ValueString(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// bindServices binds the app to the service instances of the given bindings with their names and parameters. It restores
+// changed bindings, which are deleted before the push, if the push fails.
+func (r *appResource) bindServices(ctx context.Context, appGUID string, bindings []ServiceBinding) error {
+	app, err := r.cfClient.Applications.Get(ctx, appGUID)
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		serviceInstance, err := r.cfClient.ServiceInstances.Single(ctx, &cfv3client.ServiceInstanceListOptions{
+			Names: cfv3client.Filter{
+				Values: []string{binding.ServiceInstance.ValueString()},
+			},
+			SpaceGUIDs: cfv3client.Filter{
+				Values: []string{app.Relationships.Space.Data.GUID},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to find service instance %s: %w", binding.ServiceInstance.ValueString(), err)
+		}
+		create := cfv3resource.NewServiceCredentialBindingCreateApp(serviceInstance.GUID, appGUID)
+		if !binding.Name.IsNull() {
+			create.WithName(binding.Name.ValueString())
+		}
+		if !binding.Params.IsNull() && !binding.Params.IsUnknown() {
+			create.WithJSONParameters(binding.Params.ValueString())
+		}
+		tflog.Info(ctx, fmt.Sprintf("Binding app %s to service instance %s", appGUID, binding.ServiceInstance.ValueString()))
+		jobID, _, err := r.cfClient.ServiceCredentialBindings.Create(ctx, create)
+		if err != nil {
+			return fmt.Errorf("unable to bind service instance %s: %w", binding.ServiceInstance.ValueString(), err)
+		}
+		if jobID != "" {
+			if err := pollJob(ctx, *r.cfClient, jobID, remainingTimeout(ctx)); err != nil {
+				return fmt.Errorf("unable to bind service instance %s: %w", binding.ServiceInstance.ValueString(), err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObsoleteServiceBindings(t *testing.T) {
+	binding := func(instance string, name string, params string) ServiceBinding {
+		sb := ServiceBinding{
+			ServiceInstance: types.StringValue(instance),
+			Name:            types.StringNull(),
+			Params:          jsontypes.NewNormalizedValue(params),
+		}
+		if name != "" {
+			sb.Name = types.StringValue(name)
+		}
+		return sb
+	}
+	previous := []ServiceBinding{
+		binding("db", "", "{}"),
+		binding("cache", "", "{}"),
+		binding("xsuaa", "auth", `{"xsappname":"app"}`),
+		binding("queue", "", "{}"),
+	}
+	desired := []ServiceBinding{
+		binding("db", "", "{ }"),
+		binding("cache", "redis", "{}"),
+		binding("xsuaa", "auth", `{"xsappname":"other"}`),
+		binding("logs", "", "{}"),
+	}
+
+	instances := func(bindings []ServiceBinding) []string {
+		var names []string
+		for _, sb := range bindings {
+			names = append(names, sb.ServiceInstance.ValueString())
+		}
+		return names
+	}
+	removed, changed := obsoleteServiceBindings(previous, desired)
+	assert.Equal(t, []string{"queue"}, instances(removed))
+	assert.Equal(t, []string{"cache", "xsuaa"}, instances(changed))
+
+	removed, changed = obsoleteServiceBindings(previous, previous)
+	assert.Empty(t, removed)
+	assert.Empty(t, changed)
+	removed, changed = obsoleteServiceBindings(nil, desired)
+	assert.Empty(t, removed)
+	assert.Empty(t, changed)
+}
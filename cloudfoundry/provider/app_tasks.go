@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	logclient "code.cloudfoundry.org/go-log-cache/v3"
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	taskStateSucceeded = "SUCCEEDED"
+	taskStateFailed    = "FAILED"
+	taskPollInterval   = 5 * time.Second
+)
+
+// appTask represents the subset of the CF v3 task resource used by the provider.
+type appTask struct {
+	GUID                         string `json:"guid"`
+	SequenceID                   int    `json:"sequence_id"`
+	Name                         string `json:"name"`
+	Command                      string `json:"command"`
+	State                        string `json:"state"`
+	MemoryInMB                   int    `json:"memory_in_mb"`
+	DiskInMB                     int    `json:"disk_in_mb"`
+	LogRateLimitInBytesPerSecond int    `json:"log_rate_limit_in_bytes_per_second"`
+	Result                       struct {
+		FailureReason *string `json:"failure_reason"`
+	} `json:"result"`
+	DropletGUID string    `json:"droplet_guid"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type appTaskCreate struct {
+	Command                      string `json:"command"`
+	Name                         string `json:"name,omitempty"`
+	MemoryInMB                   *int   `json:"memory_in_mb,omitempty"`
+	DiskInMB                     *int   `json:"disk_in_mb,omitempty"`
+	LogRateLimitInBytesPerSecond *int   `json:"log_rate_limit_in_bytes_per_second,omitempty"`
+}
+
+type appTaskList struct {
+	Pagination struct {
+		TotalPages int `json:"total_pages"`
+	} `json:"pagination"`
+	Resources []*appTask `json:"resources"`
+}
+
+// sourceType returns the log-cache source type of the logs of the task.
+func (t *appTask) sourceType() string {
+	return "APP/TASK/" + t.Name
+}
+
+func createTask(ctx context.Context, client *cfv3client.Client, appGUID string, create appTaskCreate) (*appTask, error) {
+	var task appTask
+	err := cfAPIRequest(ctx, client, http.MethodPost, "/v3/apps/"+appGUID+"/tasks", create, &task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func getTask(ctx context.Context, client *cfv3client.Client, taskGUID string) (*appTask, error) {
+	var task appTask
+	err := cfAPIRequest(ctx, client, http.MethodGet, "/v3/tasks/"+taskGUID, nil, &task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func cancelTask(ctx context.Context, client *cfv3client.Client, taskGUID string) error {
+	return cfAPIRequest(ctx, client, http.MethodPost, "/v3/tasks/"+taskGUID+"/actions/cancel", nil, nil)
+}
+
+// listTasks returns all tasks of the app matching the query, oldest first.
+func listTasks(ctx context.Context, client *cfv3client.Client, appGUID string, query url.Values) ([]*appTask, error) {
+	query.Set("order_by", "created_at")
+	query.Set("per_page", "100")
+	var tasks []*appTask
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var list appTaskList
+		err := cfAPIRequest(ctx, client, http.MethodGet, "/v3/apps/"+appGUID+"/tasks?"+query.Encode(), nil, &list)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, list.Resources...)
+		if page >= list.Pagination.TotalPages {
+			return tasks, nil
+		}
+	}
+}
+
+// waitForTask polls the task until it succeeded or failed. The task is cancelled if the context ends before.
+func waitForTask(ctx context.Context, client *cfv3client.Client, taskGUID string) (*appTask, error) {
+	for {
+		task, err := getTask(ctx, client, taskGUID)
+		if err != nil {
+			return nil, err
+		}
+		if task.State == taskStateSucceeded || task.State == taskStateFailed {
+			return task, nil
+		}
+		if err := sleepWithContext(ctx, taskPollInterval); err != nil {
+			// The context is done, hence the task is cancelled with a fresh one.
+			if cancelErr := cancelTask(context.WithoutCancel(ctx), client, taskGUID); cancelErr != nil {
+				tflog.Warn(ctx, fmt.Sprintf("Unable to cancel task %s: %s", taskGUID, cancelErr.Error()))
+			}
+			return nil, fmt.Errorf("task %s is still %s and was cancelled: %w", taskGUID, task.State, err)
+		}
+	}
+}
+
+// taskFailure returns the error for a failed task of the app including its last log lines, if log-cache can be read.
+func taskFailure(ctx context.Context, client *cfv3client.Client, appGUID string, task *appTask) error {
+	reason := "unknown reason"
+	if task.Result.FailureReason != nil {
+		reason = *task.Result.FailureReason
+	}
+	err := fmt.Errorf("task %s failed: %s", task.Name, reason)
+	addr, urlErr := logCacheURL(client.ApiURL("/"), "")
+	if urlErr != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Not reading task logs: %s", urlErr.Error()))
+		return err
+	}
+	tail := newLogTail(defaultLogTailLines)
+	logs := logclient.NewClient(addr, logclient.WithHTTPClient(client.HTTPAuthClient()))
+	_, readErr := readLogs(ctx, logs, appGUID, task.CreatedAt, []string{task.sourceType()}, tail.add)
+	if readErr != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Reading task logs failed: %s", readErr.Error()))
+	}
+	if len(tail.lines) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w\n\nLast task log lines:\n%s", err, strings.Join(tail.lines, "\n"))
+}
@@ -0,0 +1,510 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// routeChanges returns the desired routes which have to be mapped to the app, and the URLs of the previous routes which
+// have to be unmapped from it. A route whose protocol changed is unmapped and mapped again.
+func routeChanges(previous []Route, desired []Route) ([]Route, []string) {
+	existing := make(map[string]Route, len(previous))
+	for _, route := range previous {
+		existing[route.Route.ValueString()] = route
+	}
+	wanted := make(map[string]bool, len(desired))
+	var (
+		mapped   []Route
+		unmapped []string
+	)
+	for _, route := range desired {
+		if route.Route.IsUnknown() {
+			continue
+		}
+		url := route.Route.ValueString()
+		wanted[url] = true
+		prev, ok := existing[url]
+		switch {
+		case !ok:
+			mapped = append(mapped, route)
+		case !route.Protocol.IsNull() && attributeChanged(prev.Protocol, route.Protocol):
+			unmapped = append(unmapped, url)
+			mapped = append(mapped, route)
+		}
+	}
+	for _, route := range previous {
+		if !wanted[route.Route.ValueString()] {
+			unmapped = append(unmapped, route.Route.ValueString())
+		}
+	}
+	return mapped, unmapped
+}
+
+// sidecarChanges returns the desired sidecars which are new or changed, and the names of the previous sidecars which
+// are no longer desired. Sidecars are matched by name, so reordering them is no change.
+func sidecarChanges(previous []Sidecar, desired []Sidecar) ([]Sidecar, []string) {
+	existing := make(map[string]Sidecar, len(previous))
+	for _, sidecar := range previous {
+		existing[sidecar.Name.ValueString()] = sidecar
+	}
+	wanted := make(map[string]bool, len(desired))
+	var (
+		changed []Sidecar
+		removed []string
+	)
+	for _, sidecar := range desired {
+		wanted[sidecar.Name.ValueString()] = true
+		prev, ok := existing[sidecar.Name.ValueString()]
+		if !ok ||
+			attributeChanged(prev.Command, sidecar.Command) ||
+			attributeChanged(prev.ProcessTypes, sidecar.ProcessTypes) ||
+			attributeChanged(prev.Memory, sidecar.Memory) {
+			changed = append(changed, sidecar)
+		}
+	}
+	for _, sidecar := range previous {
+		if !wanted[sidecar.Name.ValueString()] {
+			removed = append(removed, sidecar.Name.ValueString())
+		}
+	}
+	return changed, removed
+}
+
+func routesFromSet(ctx context.Context, set types.Set) ([]Route, diag.Diagnostics) {
+	var routes []Route
+	if set.IsNull() || set.IsUnknown() {
+		return routes, nil
+	}
+	diags := set.ElementsAs(ctx, &routes, false)
+	return routes, diags
+}
+
+// unmapRoutes removes the app from the routes with the given URLs, the routes themselves are kept.
+func (r *appResource) unmapRoutes(ctx context.Context, appGUID string, urls []string) error {
+	opts := cfv3client.NewRouteListOptions()
+	opts.AppGUIDs = cfv3client.Filter{
+		Values: []string{appGUID},
+	}
+	routes, err := r.cfClient.Routes.ListAll(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("unable to list the routes of app %s: %w", appGUID, err)
+	}
+	remove := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		remove[url] = true
+	}
+	for _, route := range routes {
+		if !remove[route.URL] {
+			continue
+		}
+		for _, destination := range route.Destinations {
+			if destination.GUID == nil || destination.App.GUID == nil || *destination.App.GUID != appGUID {
+				continue
+			}
+			tflog.Info(ctx, fmt.Sprintf("Unmapping route %s from app %s", route.URL, appGUID))
+			if err := r.cfClient.Routes.RemoveDestination(ctx, route.GUID, *destination.GUID); err != nil {
+				return fmt.Errorf("unable to unmap route %s: %w", route.URL, err)
+			}
+		}
+	}
+	return nil
+}
+
+// mapRoutes maps the app to the given routes, routes which do not exist yet are created in the space of the app.
+func (r *appResource) mapRoutes(ctx context.Context, appGUID string, routes []Route) error {
+	app, err := r.cfClient.Applications.Get(ctx, appGUID)
+	if err != nil {
+		return err
+	}
+	for _, desired := range routes {
+		route, err := r.findOrCreateRoute(ctx, app.Relationships.Space.Data.GUID, desired.Route.ValueString())
+		if err != nil {
+			return err
+		}
+		destination := cfv3resource.NewRouteDestinationInsertOrReplace(appGUID)
+		if !desired.Protocol.IsNull() && !desired.Protocol.IsUnknown() {
+			destination.WithProtocol(desired.Protocol.ValueString())
+		}
+		tflog.Info(ctx, fmt.Sprintf("Mapping route %s to app %s", route.URL, appGUID))
+		_, err = r.cfClient.Routes.InsertDestinations(ctx, route.GUID, []*cfv3resource.RouteDestinationInsertOrReplace{destination})
+		if err != nil {
+			return fmt.Errorf("unable to map route %s: %w", route.URL, err)
+		}
+	}
+	return nil
+}
+
+// splitRouteURL splits a route URL like 'host.domain:port/path' into its host name, port and path.
+func splitRouteURL(routeURL string) (string, int, string, error) {
+	hostName, routePath, _ := strings.Cut(routeURL, "/")
+	if routePath != "" {
+		routePath = "/" + routePath
+	}
+	port := 0
+	if name, rawPort, ok := strings.Cut(hostName, ":"); ok {
+		p, err := strconv.Atoi(rawPort)
+		if err != nil {
+			return "", 0, "", fmt.Errorf("invalid port in route %s", routeURL)
+		}
+		hostName, port = name, p
+	}
+	return hostName, port, routePath, nil
+}
+
+// findOrCreateRoute returns the route with the given URL, it is created in the space if it does not exist yet. A domain
+// matching the whole host name of the URL takes precedence over its parent domain with the first label as host.
+func (r *appResource) findOrCreateRoute(ctx context.Context, spaceGUID string, routeURL string) (*cfv3resource.Route, error) {
+	hostName, port, routePath, err := splitRouteURL(routeURL)
+	if err != nil {
+		return nil, err
+	}
+	host, parentDomain, _ := strings.Cut(hostName, ".")
+	domainOpts := cfv3client.NewDomainListOptions()
+	domainOpts.Names = cfv3client.Filter{
+		Values: []string{hostName, parentDomain},
+	}
+	domains, err := r.cfClient.Domains.ListAll(ctx, domainOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up the domain of route %s: %w", routeURL, err)
+	}
+	byName := make(map[string]*cfv3resource.Domain, len(domains))
+	for _, domain := range domains {
+		byName[domain.Name] = domain
+	}
+	domain, ok := byName[hostName]
+	if ok {
+		host = ""
+	} else if domain, ok = byName[parentDomain]; !ok {
+		return nil, fmt.Errorf("no domain found for route %s", routeURL)
+	}
+
+	routeOpts := cfv3client.NewRouteListOptions()
+	routeOpts.DomainGUIDs = cfv3client.Filter{
+		Values: []string{domain.GUID},
+	}
+	routeOpts.SpaceGUIDs = cfv3client.Filter{
+		Values: []string{spaceGUID},
+	}
+	routes, err := r.cfClient.Routes.ListAll(ctx, routeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up route %s: %w", routeURL, err)
+	}
+	for _, route := range routes {
+		routePort := 0
+		if route.Port != nil {
+			routePort = *route.Port
+		}
+		if route.Host == host && route.Path == routePath && routePort == port {
+			return route, nil
+		}
+	}
+
+	create := cfv3resource.NewRouteCreate(domain.GUID, spaceGUID)
+	if host != "" {
+		create.Host = &host
+	}
+	if routePath != "" {
+		create.Path = &routePath
+	}
+	if port != 0 {
+		create.Port = &port
+	}
+	tflog.Info(ctx, fmt.Sprintf("Creating route %s", routeURL))
+	route, err := r.cfClient.Routes.Create(ctx, create)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create route %s: %w", routeURL, err)
+	}
+	return route, nil
+}
+
+// updateRoutes unmaps the app from the given route URLs and maps it to the given routes, as returned by routeChanges.
+// The routes themselves are neither deleted nor is the app restarted.
+func (r *appResource) updateRoutes(ctx context.Context, appGUID string, unmapped []string, mapped []Route) error {
+	if len(unmapped) > 0 {
+		if err := r.unmapRoutes(ctx, appGUID, unmapped); err != nil {
+			return err
+		}
+	}
+	if len(mapped) > 0 {
+		return r.mapRoutes(ctx, appGUID, mapped)
+	}
+	return nil
+}
+
+// deleteSidecars deletes the sidecars of the app with the given names.
+func (r *appResource) deleteSidecars(ctx context.Context, appGUID string, names []string) error {
+	sidecars, err := r.cfClient.Sidecars.ListForAppAll(ctx, appGUID, nil)
+	if err != nil {
+		return fmt.Errorf("unable to list the sidecars of app %s: %w", appGUID, err)
+	}
+	remove := make(map[string]bool, len(names))
+	for _, name := range names {
+		remove[name] = true
+	}
+	for _, sidecar := range sidecars {
+		if !remove[sidecar.Name] {
+			continue
+		}
+		tflog.Info(ctx, fmt.Sprintf("Deleting sidecar %s of app %s", sidecar.Name, appGUID))
+		if err := r.cfClient.Sidecars.Delete(ctx, sidecar.GUID); err != nil {
+			return fmt.Errorf("unable to delete sidecar %s: %w", sidecar.Name, err)
+		}
+	}
+	return nil
+}
+
+type appSidecar struct {
+	Name         string   `json:"name,omitempty"`
+	Command      string   `json:"command,omitempty"`
+	ProcessTypes []string `json:"process_types,omitempty"`
+	MemoryInMB   *int     `json:"memory_in_mb,omitempty"`
+}
+
+func newAppSidecar(ctx context.Context, sidecar Sidecar) (appSidecar, error) {
+	body := appSidecar{
+		Name:    sidecar.Name.ValueString(),
+		Command: sidecar.Command.ValueString(),
+	}
+	if !sidecar.ProcessTypes.IsNull() && !sidecar.ProcessTypes.IsUnknown() {
+		if diags := sidecar.ProcessTypes.ElementsAs(ctx, &body.ProcessTypes, false); diags.HasError() {
+			return body, fmt.Errorf("invalid process types of sidecar %s: %s", body.Name, diags.Errors()[0].Detail())
+		}
+	}
+	if !sidecar.Memory.IsNull() && !sidecar.Memory.IsUnknown() {
+		memory, err := quantityInUnit(sidecar.Memory.ValueString(), "M")
+		if err != nil {
+			return body, fmt.Errorf("invalid memory of sidecar %s: %w", body.Name, err)
+		}
+		body.MemoryInMB = &memory
+	}
+	return body, nil
+}
+
+// updateSidecars creates, updates and deletes the sidecars of the app with the sidecars API, so that they match the
+// desired sidecars. It reports whether a sidecar changed, which only takes effect once the app is restarted.
+func (r *appResource) updateSidecars(ctx context.Context, appGUID string, previous []Sidecar, desired []Sidecar) (bool, error) {
+	changed, removed := sidecarChanges(previous, desired)
+	if len(removed) > 0 {
+		if err := r.deleteSidecars(ctx, appGUID, removed); err != nil {
+			return false, err
+		}
+	}
+	if len(changed) == 0 {
+		return len(removed) > 0, nil
+	}
+	sidecars, err := r.cfClient.Sidecars.ListForAppAll(ctx, appGUID, nil)
+	if err != nil {
+		return false, fmt.Errorf("unable to list the sidecars of app %s: %w", appGUID, err)
+	}
+	existing := make(map[string]string, len(sidecars))
+	for _, sidecar := range sidecars {
+		existing[sidecar.Name] = sidecar.GUID
+	}
+	for _, sidecar := range changed {
+		body, err := newAppSidecar(ctx, sidecar)
+		if err != nil {
+			return false, err
+		}
+		if guid, ok := existing[body.Name]; ok {
+			tflog.Info(ctx, fmt.Sprintf("Updating sidecar %s of app %s", body.Name, appGUID))
+			err = cfAPIRequest(ctx, r.cfClient, http.MethodPatch, "/v3/sidecars/"+guid, body, nil)
+		} else {
+			tflog.Info(ctx, fmt.Sprintf("Creating sidecar %s of app %s", body.Name, appGUID))
+			err = cfAPIRequest(ctx, r.cfClient, http.MethodPost, "/v3/apps/"+appGUID+"/sidecars", body, nil)
+		}
+		if err != nil {
+			return false, fmt.Errorf("unable to apply sidecar %s: %w", body.Name, err)
+		}
+	}
+	return true, nil
+}
+
+// attributeChanged reports whether the planned value differs from the previous one, unknown values are not changes.
+func attributeChanged(previous attr.Value, desired attr.Value) bool {
+	return !desired.IsUnknown() && !desired.Equal(previous)
+}
+
+// appUpdateRequiresPush reports whether the update changes attributes which are only applied by pushing the app,
+// e.g. its bits, buildpacks, stack, memory or health checks, so that they are rolled out with the app's strategy.
+// Changes of the instances, metadata, features, ssh, environment, routes and sidecars of the app, and stopping it, are
+// applied without a push.
+func appUpdateRequiresPush(previous *AppType, desired *AppType) bool {
+	pairs := [][2]attr.Value{
+		{previous.Name, desired.Name},
+		{previous.Stack, desired.Stack},
+		{previous.Buildpacks, desired.Buildpacks},
+		{previous.Path, desired.Path},
+		{previous.SourceCodeHash, desired.SourceCodeHash},
+		{previous.SourceCodeDigest, desired.SourceCodeDigest},
+		{previous.DockerImage, desired.DockerImage},
+		{previous.Droplet, desired.Droplet},
+		{previous.ServiceBindings, desired.ServiceBindings},
+		{previous.NoRoute, desired.NoRoute},
+		{previous.RandomRoute, desired.RandomRoute},
+		{previous.Memory, desired.Memory},
+		{previous.DiskQuota, desired.DiskQuota},
+		{previous.LogRateLimitPerSecond, desired.LogRateLimitPerSecond},
+		{previous.Command, desired.Command},
+		{previous.HealthCheckType, desired.HealthCheckType},
+		{previous.HealthCheckHttpEndpoint, desired.HealthCheckHttpEndpoint},
+		{previous.HealthCheckInvocationTimeout, desired.HealthCheckInvocationTimeout},
+		{previous.HealthCheckInterval, desired.HealthCheckInterval},
+		{previous.Timeout, desired.Timeout},
+		{previous.ReadinessHealthCheckType, desired.ReadinessHealthCheckType},
+		{previous.ReadinessHealthCheckHttpEndpoint, desired.ReadinessHealthCheckHttpEndpoint},
+		{previous.ReadinessHealthCheckInvocationTimeout, desired.ReadinessHealthCheckInvocationTimeout},
+		{previous.ReadinessHealthCheckInterval, desired.ReadinessHealthCheckInterval},
+	}
+	for _, pair := range pairs {
+		if attributeChanged(pair[0], pair[1]) {
+			return true
+		}
+	}
+	// starting a stopped app may require staging it
+	if previous.Stopped.ValueBool() && !desired.Stopped.ValueBool() {
+		return true
+	}
+	return dockerCredentialsChanged(previous.DockerCredentials, desired.DockerCredentials) ||
+		processesRequirePush(previous.Processes, desired.Processes)
+}
+
+func dockerCredentialsChanged(previous *DockerCredentials, desired *DockerCredentials) bool {
+	if previous == nil || desired == nil {
+		return (previous == nil) != (desired == nil)
+	}
+	return attributeChanged(previous.Username, desired.Username) || attributeChanged(previous.Password, desired.Password)
+}
+
+// processesRequirePush reports whether processes are added or removed, or settings other than their instances changed.
+func processesRequirePush(previous []Process, desired []Process) bool {
+	if len(previous) != len(desired) {
+		return true
+	}
+	existing := make(map[string]Process, len(previous))
+	for _, process := range previous {
+		existing[process.Type.ValueString()] = process
+	}
+	for _, d := range desired {
+		p, ok := existing[d.Type.ValueString()]
+		if !ok {
+			return true
+		}
+		pairs := [][2]attr.Value{
+			{p.Memory, d.Memory},
+			{p.DiskQuota, d.DiskQuota},
+			{p.LogRateLimitPerSecond, d.LogRateLimitPerSecond},
+			{p.Command, d.Command},
+			{p.HealthCheckType, d.HealthCheckType},
+			{p.HealthCheckHttpEndpoint, d.HealthCheckHttpEndpoint},
+			{p.HealthCheckInvocationTimeout, d.HealthCheckInvocationTimeout},
+			{p.HealthCheckInterval, d.HealthCheckInterval},
+			{p.Timeout, d.Timeout},
+			{p.ReadinessHealthCheckType, d.ReadinessHealthCheckType},
+			{p.ReadinessHealthCheckHttpEndpoint, d.ReadinessHealthCheckHttpEndpoint},
+			{p.ReadinessHealthCheckInvocationTimeout, d.ReadinessHealthCheckInvocationTimeout},
+			{p.ReadinessHealthCheckInterval, d.ReadinessHealthCheckInterval},
+		}
+		for _, pair := range pairs {
+			if attributeChanged(pair[0], pair[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// processScaling returns the instances of the processes whose instances changed, keyed by process type. The app level
+// instances belong to the web process. Other scaling changes require a push, see appUpdateRequiresPush.
+func processScaling(previous *AppType, desired *AppType) map[string]AppProcessType {
+	scaling := map[string]AppProcessType{}
+	add := func(processType string, previousInstances types.Int64, desiredInstances types.Int64) {
+		if instancesChanged(previousInstances, desiredInstances) {
+			scaling[processType] = AppProcessType{Instances: desiredInstances}
+		}
+	}
+	add("web", previous.Instances, desired.Instances)
+	existing := make(map[string]Process, len(previous.Processes))
+	for _, process := range previous.Processes {
+		existing[process.Type.ValueString()] = process
+	}
+	for _, process := range desired.Processes {
+		processType := process.Type.ValueString()
+		if _, ok := scaling[processType]; ok {
+			continue
+		}
+		add(processType, existing[processType].Instances, process.Instances)
+	}
+	return scaling
+}
+
+// updateInPlace applies an update which does not require a push. The metadata of the app is patched, the instances of
+// its processes are scaled and it is stopped or restarted with its strategy if needed, so that the app is not restaged.
+func (r *appResource) updateInPlace(ctx context.Context, previous *AppType, desired *AppType, metadata *cfv3resource.Metadata, restart bool) (*cfv3resource.App, error) {
+	appGUID := previous.ID.ValueString()
+	tflog.Info(ctx, "Updating app without pushing it", map[string]interface{}{"app": appGUID})
+	var (
+		app *cfv3resource.App
+		err error
+	)
+	if attributeChanged(previous.Labels, desired.Labels) || attributeChanged(previous.Annotations, desired.Annotations) {
+		app, err = r.cfClient.Applications.Update(ctx, appGUID, &cfv3resource.AppUpdate{Metadata: metadata})
+		if err != nil {
+			return nil, fmt.Errorf("unable to update the metadata of app %s: %w", appGUID, err)
+		}
+	} else {
+		app, err = r.cfClient.Applications.Get(ctx, appGUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := r.scaleProcesses(ctx, appGUID, processScaling(previous, desired)); err != nil {
+		return nil, err
+	}
+	switch {
+	case desired.Stopped.ValueBool() && !previous.Stopped.ValueBool():
+		tflog.Info(ctx, fmt.Sprintf("Stopping app %s", appGUID))
+		app, err = r.cfClient.Applications.Stop(ctx, appGUID)
+	case restart && !desired.Stopped.ValueBool():
+		tflog.Info(ctx, fmt.Sprintf("Restarting app %s to apply its features and sidecars", appGUID))
+		err = r.rolloutDroplet(ctx, appGUID, "", restartStrategy(*desired), *desired)
+		if err == nil {
+			app, err = r.cfClient.Applications.Get(ctx, appGUID)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to change the state of app %s: %w", appGUID, err)
+	}
+	return app, nil
+}
+
+// scaleProcesses scales the processes of the app of the given types, which only sets the values that differ.
+func (r *appResource) scaleProcesses(ctx context.Context, appGUID string, scaling map[string]AppProcessType) error {
+	for processType, plan := range scaling {
+		current, err := getAppProcess(ctx, r.cfClient, appGUID, processType)
+		if err != nil {
+			return fmt.Errorf("unable to read the %s process of app %s: %w", processType, appGUID, err)
+		}
+		scale, diags := plan.mapProcessScaleTypeToValues(current)
+		if diags.HasError() {
+			return fmt.Errorf("invalid scaling of the %s process: %s", processType, diags.Errors()[0].Detail())
+		}
+		if scale == nil {
+			continue
+		}
+		tflog.Info(ctx, fmt.Sprintf("Scaling the %s process of app %s", processType, appGUID))
+		if _, err := scaleProcess(ctx, r.cfClient, current.GUID, scale); err != nil {
+			return fmt.Errorf("unable to scale the %s process of app %s: %w", processType, appGUID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteChanges(t *testing.T) {
+	route := func(url string, protocol string) Route {
+		return Route{Route: types.StringValue(url), Protocol: types.StringValue(protocol)}
+	}
+	previous := []Route{route("a.example.com", "http1"), route("b.example.com", "http1"), route("c.example.com/path", "http1")}
+	desired := []Route{route("b.example.com", "http2"), route("d.example.com", "http1"), route("a.example.com", "http1")}
+
+	mapped, unmapped := routeChanges(previous, desired)
+	assert.Equal(t, []Route{route("b.example.com", "http2"), route("d.example.com", "http1")}, mapped)
+	assert.Equal(t, []string{"b.example.com", "c.example.com/path"}, unmapped)
+
+	mapped, unmapped = routeChanges(previous, previous)
+	assert.Empty(t, mapped)
+	assert.Empty(t, unmapped)
+
+	unknownProtocol := []Route{{Route: types.StringValue("a.example.com"), Protocol: types.StringUnknown()}}
+	mapped, unmapped = routeChanges(previous[:1], unknownProtocol)
+	assert.Empty(t, mapped)
+	assert.Empty(t, unmapped)
+}
+
+func TestSplitRouteURL(t *testing.T) {
+	for url, expected := range map[string]struct {
+		hostName string
+		port     int
+		path     string
+	}{
+		"app.example.com":          {"app.example.com", 0, ""},
+		"app.example.com/api/v1":   {"app.example.com", 0, "/api/v1"},
+		"tcp.example.com:1024":     {"tcp.example.com", 1024, ""},
+		"example.com:61000/ignore": {"example.com", 61000, "/ignore"},
+	} {
+		hostName, port, path, err := splitRouteURL(url)
+		assert.NoError(t, err, url)
+		assert.Equal(t, expected.hostName, hostName, url)
+		assert.Equal(t, expected.port, port, url)
+		assert.Equal(t, expected.path, path, url)
+	}
+	_, _, _, err := splitRouteURL("tcp.example.com:port")
+	assert.Error(t, err)
+}
+
+func TestSidecarChanges(t *testing.T) {
+	sidecar := func(name string, memory string) Sidecar {
+		return Sidecar{Name: types.StringValue(name), Command: types.StringValue("run"), ProcessTypes: types.SetNull(types.StringType), Memory: types.StringValue(memory)}
+	}
+	previous := []Sidecar{sidecar("proxy", "64M"), sidecar("agent", "32M")}
+
+	changed, removed := sidecarChanges(previous, []Sidecar{sidecar("agent", "32M"), sidecar("proxy", "64M")})
+	assert.Empty(t, changed)
+	assert.Empty(t, removed)
+
+	changed, removed = sidecarChanges(previous, []Sidecar{sidecar("proxy", "128M"), sidecar("logs", "32M")})
+	assert.Equal(t, []Sidecar{sidecar("proxy", "128M"), sidecar("logs", "32M")}, changed)
+	assert.Equal(t, []string{"agent"}, removed)
+
+	changed, removed = sidecarChanges(previous, nil)
+	assert.Empty(t, changed)
+	assert.Equal(t, []string{"proxy", "agent"}, removed)
+}
+
+func TestAppUpdateRequiresPush(t *testing.T) {
+	previous := AppType{
+		Name:             types.StringValue("app"),
+		Stack:            types.StringValue("cflinuxfs4"),
+		Path:             types.StringValue("app.zip"),
+		SourceCodeDigest: types.StringValue("digest"),
+		Buildpacks:       types.ListNull(types.StringType),
+		ServiceBindings:  types.SetNull(serviceBindingObjType),
+		Instances:        types.Int64Value(1),
+		Memory:           types.StringValue("256M"),
+		Stopped:          types.BoolValue(false),
+		Processes:        []Process{{Type: types.StringValue("worker"), Command: types.StringValue("run"), Instances: types.Int64Value(1)}},
+	}
+	desired := previous
+	desired.Processes = []Process{{Type: types.StringValue("worker"), Command: types.StringValue("run"), Instances: types.Int64Value(3)}}
+	desired.Instances = types.Int64Value(2)
+	desired.Stack = types.StringUnknown()
+	desired.Stopped = types.BoolValue(true)
+	assert.False(t, appUpdateRequiresPush(&previous, &desired))
+
+	memory := desired
+	memory.Memory = types.StringValue("512M")
+	assert.True(t, appUpdateRequiresPush(&previous, &memory))
+
+	bits := desired
+	bits.SourceCodeDigest = types.StringValue("changed")
+	assert.True(t, appUpdateRequiresPush(&previous, &bits))
+
+	stack := desired
+	stack.Stack = types.StringValue("cflinuxfs5")
+	assert.True(t, appUpdateRequiresPush(&previous, &stack))
+
+	command := desired
+	command.Processes = []Process{{Type: types.StringValue("worker"), Command: types.StringValue("run --fast"), Instances: types.Int64Value(3)}}
+	assert.True(t, appUpdateRequiresPush(&previous, &command))
+
+	started := previous
+	started.Stopped = types.BoolValue(true)
+	assert.True(t, appUpdateRequiresPush(&started, &previous))
+}
+
+func TestProcessScaling(t *testing.T) {
+	previous := AppType{
+		Instances: types.Int64Value(1),
+		Memory:    types.StringValue("256M"),
+		Processes: []Process{
+			{Type: types.StringValue("worker"), Instances: types.Int64Value(1), Memory: types.StringValue("128M")},
+			{Type: types.StringValue("clock"), Instances: types.Int64Value(1)},
+		},
+	}
+	desired := previous
+	desired.Memory = types.StringValue("512M")
+	desired.Processes = []Process{
+		{Type: types.StringValue("worker"), Instances: types.Int64Value(4), Memory: types.StringValue("128M")},
+		{Type: types.StringValue("clock"), Instances: types.Int64Null()},
+	}
+
+	scaling := processScaling(&previous, &desired)
+	assert.Len(t, scaling, 1)
+	assert.Equal(t, int64(4), scaling["worker"].Instances.ValueInt64())
+	assert.True(t, scaling["worker"].Memory.IsNull())
+
+	desired.Instances = types.Int64Value(2)
+	scaling = processScaling(&previous, &desired)
+	assert.Equal(t, int64(2), scaling["web"].Instances.ValueInt64())
+
+	assert.Empty(t, processScaling(&previous, &previous))
+}
@@ -47,6 +47,20 @@ func (d *appDataSource) Schema(ctx context.Context, req datasource.SchemaRequest
 				MarkdownDescription: "The name of the associated Cloud Foundry organization to look up",
 				Required:            true,
 			},
+			"sensitive_environment_keys": schema.SetAttribute{
+				MarkdownDescription: "The names of the environment variables whose values are redacted in `environment`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"include_features": schema.BoolAttribute{
+				MarkdownDescription: "Whether to read the app features into `features`, which takes an additional request. Defaults to false.",
+				Optional:            true,
+			},
+			"features": schema.MapAttribute{
+				MarkdownDescription: "Whether the 'file-based-vcap-services', 'revisions' and 'service-binding-k8s' app features are enabled, by feature name. Only read if `include_features` is set.",
+				ElementType:         types.BoolType,
+				Computed:            true,
+			},
 			"enable_ssh": schema.BoolAttribute{
 				MarkdownDescription: "Whether SSH access is enabled or disabled on an app level.",
 				Computed:            true,
@@ -84,6 +98,10 @@ func (d *appDataSource) Schema(ctx context.Context, req datasource.SchemaRequest
 							MarkdownDescription: "The service instance name.",
 							Computed:            true,
 						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the service binding.",
+							Computed:            true,
+						},
 						"params": schema.StringAttribute{
 							CustomType:          jsontypes.NormalizedType{},
 							MarkdownDescription: "A json object to represent the parameters for the service instance.",
@@ -306,7 +324,13 @@ func (d *appDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		resp.Diagnostics.AddError("Error unmarshalling app", err.Error())
 		return
 	}
-	atResp, diags := mapAppDatasourceValuesToType(ctx, appManifest.Applications[0], app, sshResp)
+	atResp, diags := mapAppDatasourceValuesToType(ctx, appManifest.Applications[0], app, sshResp, datasourceAppType.SensitiveEnvironmentKeys)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	atResp.IncludeFeatures = datasourceAppType.IncludeFeatures
+	atResp.Features, diags = readManagedAppFeatures(ctx, d.cfClient, app.GUID, datasourceAppType.IncludeFeatures)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
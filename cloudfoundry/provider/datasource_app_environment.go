@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &appEnvironmentDataSource{}
+var _ datasource.DataSourceWithConfigure = &appEnvironmentDataSource{}
+
+func NewAppEnvironmentDataSource() datasource.DataSource {
+	return &appEnvironmentDataSource{}
+}
+
+type appEnvironmentDataSource struct {
+	cfClient *cfv3client.Client
+}
+
+func (d *appEnvironmentDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_environment"
+}
+
+func (d *appEnvironmentDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	session, ok := req.ProviderData.(*managers.Session)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *managers.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.cfClient = session.CFClient
+}
+
+func (d *appEnvironmentDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Gets the effective environment of a Cloud Foundry application, including the credentials of the bound service instances in `VCAP_SERVICES` and the environment variable groups. Reading the environment requires the space developer role. All values which may contain secrets are sensitive.",
+		Attributes: map[string]schema.Attribute{
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the application",
+				Required:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+			},
+			"environment": schema.MapAttribute{
+				MarkdownDescription: "The environment variables set on the application",
+				Computed:            true,
+				Sensitive:           true,
+				ElementType:         types.StringType,
+			},
+			"running_environment_group": schema.MapAttribute{
+				MarkdownDescription: "The variables of the running environment variable group, values which are not strings are JSON encoded",
+				Computed:            true,
+				Sensitive:           true,
+				ElementType:         types.StringType,
+			},
+			"staging_environment_group": schema.MapAttribute{
+				MarkdownDescription: "The variables of the staging environment variable group, values which are not strings are JSON encoded",
+				Computed:            true,
+				Sensitive:           true,
+				ElementType:         types.StringType,
+			},
+			"vcap_services": schema.StringAttribute{
+				MarkdownDescription: "The `VCAP_SERVICES` of the application as JSON",
+				Computed:            true,
+				Sensitive:           true,
+				CustomType:          jsontypes.NormalizedType{},
+			},
+			"vcap_application": schema.StringAttribute{
+				MarkdownDescription: "The `VCAP_APPLICATION` of the application as JSON",
+				Computed:            true,
+				CustomType:          jsontypes.NormalizedType{},
+			},
+			"services": schema.ListNestedAttribute{
+				MarkdownDescription: "The bound service instances parsed from `VCAP_SERVICES`, ordered by label",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"label": schema.StringAttribute{
+							MarkdownDescription: "The label of the service offering, or 'user-provided'",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The binding name if set, otherwise the name of the service instance",
+							Computed:            true,
+						},
+						"instance_name": schema.StringAttribute{
+							MarkdownDescription: "The name of the service instance",
+							Computed:            true,
+						},
+						"binding_name": schema.StringAttribute{
+							MarkdownDescription: "The name of the service binding",
+							Computed:            true,
+						},
+						"plan": schema.StringAttribute{
+							MarkdownDescription: "The name of the service plan",
+							Computed:            true,
+						},
+						"tags": schema.ListAttribute{
+							MarkdownDescription: "The tags of the service instance",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"credentials": schema.StringAttribute{
+							MarkdownDescription: "The credentials of the binding as JSON",
+							Computed:            true,
+							Sensitive:           true,
+							CustomType:          jsontypes.NormalizedType{},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *appEnvironmentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var data appEnvironmentDatasourceType
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	env, err := getAppEnvironment(ctx, d.cfClient, data.App.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Fetching app environment",
+			"Could not read the environment of app "+data.App.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+
+	data, diags = mapAppEnvironmentValuesToType(ctx, env, data)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Trace(ctx, "read the app environment data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
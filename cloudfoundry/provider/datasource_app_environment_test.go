@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAppEnvironmentDataSource_Configure(t *testing.T) {
+	t.Parallel()
+	dataSourceName := "data.cloudfoundry_app_environment.ds"
+	t.Run("happy path - read app environment", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_app_environment")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+data "cloudfoundry_app_environment" "ds" {
+	app = data.cloudfoundry_app.app.id
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr(dataSourceName, "environment.MY_ENV", "red"),
+						resource.TestCheckResourceAttr(dataSourceName, "services.0.instance_name", "xsuaa-tf"),
+						resource.TestCheckResourceAttrSet(dataSourceName, "vcap_application"),
+					),
+				},
+			},
+		})
+	})
+	t.Run("error path - read environment of unavailable app", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_app_environment_invalid")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app_environment" "ds" {
+	app = "ec6ac2b3-fb79-43c4-9734-000d4299bd59"
+}
+					`,
+					ExpectError: regexp.MustCompile(`API Error Fetching app environment`),
+				},
+			},
+		})
+	})
+}
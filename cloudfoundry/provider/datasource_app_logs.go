@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	logclient "code.cloudfoundry.org/go-log-cache/v3"
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultLogEntriesLimit is the number of log entries returned if no limit is configured.
+const defaultLogEntriesLimit = 100
+
+var _ datasource.DataSource = &appLogsDataSource{}
+var _ datasource.DataSourceWithConfigure = &appLogsDataSource{}
+
+func NewAppLogsDataSource() datasource.DataSource {
+	return &appLogsDataSource{}
+}
+
+type appLogsDataSource struct {
+	cfClient *cfv3client.Client
+}
+
+func (d *appLogsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_logs"
+}
+
+func (d *appLogsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	session, ok := req.ProviderData.(*managers.Session)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *managers.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.cfClient = session.CFClient
+}
+
+func (d *appLogsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Gets the most recent logs of a Cloud Foundry application from log-cache, e.g. to check the startup log lines of an app in a smoke test. Log-cache only retains the recent envelopes of an app.",
+		Attributes: map[string]schema.Attribute{
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the application",
+				Required:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+			},
+			"start_time": schema.StringAttribute{
+				MarkdownDescription: "Only return entries at or after this time, in RFC3339 format",
+				Optional:            true,
+				Validators: []validator.String{
+					validation.ValidRFC3339(),
+					stringvalidator.ConflictsWith(path.MatchRoot("since")),
+				},
+			},
+			"end_time": schema.StringAttribute{
+				MarkdownDescription: "Only return entries before this time, in RFC3339 format. Defaults to now",
+				Optional:            true,
+				Validators: []validator.String{
+					validation.ValidRFC3339(),
+				},
+			},
+			"since": schema.StringAttribute{
+				MarkdownDescription: "Only return entries of the given duration before now, e.g. 15m or 1h",
+				Optional:            true,
+				Validators: []validator.String{
+					validation.ValidDuration(),
+				},
+			},
+			"envelope_types": schema.SetAttribute{
+				MarkdownDescription: "The envelope types to return, any of 'LOG', 'COUNTER', 'GAUGE', 'TIMER' and 'EVENT'. Defaults to 'LOG'",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.OneOf("LOG", "COUNTER", "GAUGE", "TIMER", "EVENT")),
+				},
+			},
+			"source_types": schema.SetAttribute{
+				MarkdownDescription: "The source types to return, e.g. `STG` for staging and `APP` for the app instances. A source type matches all source types starting with it, so `APP` includes `APP/PROC/WEB`. Defaults to all source types",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of entries to return, the most recent entries are returned. Defaults to 100",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 10000),
+				},
+			},
+			"log_cache_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of log-cache. Defaults to the API URL with its `api.` host prefix replaced by `log-cache.`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "The log entries, oldest first",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"timestamp": schema.StringAttribute{
+							MarkdownDescription: "The time of the entry in RFC3339 format",
+							Computed:            true,
+						},
+						"source_type": schema.StringAttribute{
+							MarkdownDescription: "The source type of the entry, e.g. `APP/PROC/WEB` or `STG`",
+							Computed:            true,
+						},
+						"instance": schema.StringAttribute{
+							MarkdownDescription: "The instance index of the source",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The envelope type of the entry",
+							Computed:            true,
+						},
+						"stream": schema.StringAttribute{
+							MarkdownDescription: "The stream of a log entry, 'OUT' or 'ERR'",
+							Computed:            true,
+						},
+						"message": schema.StringAttribute{
+							MarkdownDescription: "The log line, or a description of the values of a metric",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *appLogsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var data appLogsDatasourceType
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	now := time.Now()
+	query := recentLogsQuery{
+		start:         time.Unix(0, 0),
+		end:           now,
+		envelopeTypes: []logcache_v1.EnvelopeType{logcache_v1.EnvelopeType_LOG},
+		limit:         defaultLogEntriesLimit,
+	}
+	if !data.StartTime.IsNull() {
+		query.start, _ = time.Parse(time.RFC3339, data.StartTime.ValueString())
+	}
+	if !data.Since.IsNull() {
+		since, _ := time.ParseDuration(data.Since.ValueString())
+		query.start = now.Add(-since)
+	}
+	if !data.EndTime.IsNull() {
+		query.end, _ = time.Parse(time.RFC3339, data.EndTime.ValueString())
+	}
+	if !data.EnvelopeTypes.IsNull() {
+		var envelopeTypes []string
+		resp.Diagnostics.Append(data.EnvelopeTypes.ElementsAs(ctx, &envelopeTypes, false)...)
+		query.envelopeTypes = nil
+		for _, envelopeType := range envelopeTypes {
+			query.envelopeTypes = append(query.envelopeTypes, logcache_v1.EnvelopeType(logcache_v1.EnvelopeType_value[envelopeType]))
+		}
+	}
+	if !data.SourceTypes.IsNull() {
+		resp.Diagnostics.Append(data.SourceTypes.ElementsAs(ctx, &query.sourceTypes, false)...)
+	}
+	if !data.Limit.IsNull() {
+		query.limit = int(data.Limit.ValueInt64())
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	addr, err := logCacheURL(d.cfClient.ApiURL("/"), data.LogCacheURL.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("log_cache_url"),
+			"Unable to determine the log-cache URL",
+			err.Error(),
+		)
+		return
+	}
+	client := logclient.NewClient(addr, logclient.WithHTTPClient(d.cfClient.HTTPAuthClient()))
+	envelopes, err := readRecentEnvelopes(ctx, client, data.App.ValueString(), query)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Fetching app logs",
+			"Could not read the logs of app "+data.App.ValueString()+" from "+addr+" : "+err.Error(),
+		)
+		return
+	}
+
+	data.Entries = mapLogEnvelopesValuesToType(envelopes)
+
+	tflog.Trace(ctx, "read the app logs data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
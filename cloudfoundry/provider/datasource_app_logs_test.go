@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAppLogsDataSource_Configure(t *testing.T) {
+	t.Parallel()
+	dataSourceName := "data.cloudfoundry_app_logs.ds"
+	t.Run("happy path - read recent app logs", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_app_logs")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+data "cloudfoundry_app_logs" "ds" {
+	app            = data.cloudfoundry_app.app.id
+	envelope_types = ["LOG"]
+	limit          = 10
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet(dataSourceName, "entries.#"),
+						resource.TestCheckResourceAttr(dataSourceName, "entries.0.type", "LOG"),
+						resource.TestCheckResourceAttrSet(dataSourceName, "entries.0.message"),
+					),
+				},
+			},
+		})
+	})
+	t.Run("error path - read logs of unavailable app", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_app_logs_invalid")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app_logs" "ds" {
+	app = "ec6ac2b3-fb79-43c4-9734-000d4299bd59"
+}
+					`,
+					ExpectError: regexp.MustCompile(`API Error Fetching app logs`),
+				},
+			},
+		})
+	})
+}
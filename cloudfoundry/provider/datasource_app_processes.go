@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &appProcessesDataSource{}
+var _ datasource.DataSourceWithConfigure = &appProcessesDataSource{}
+
+func NewAppProcessesDataSource() datasource.DataSource {
+	return &appProcessesDataSource{}
+}
+
+type appProcessesDataSource struct {
+	cfClient *cfv3client.Client
+}
+
+func (d *appProcessesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_processes"
+}
+
+func (d *appProcessesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	session, ok := req.ProviderData.(*managers.Session)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *managers.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.cfClient = session.CFClient
+}
+
+func (d *appProcessesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Gets the runtime state of the processes of a Cloud Foundry application, including the state and resource usage of every instance.",
+		Attributes: map[string]schema.Attribute{
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the application",
+				Required:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The process type to filter by, e.g. web",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"processes": schema.ListNestedAttribute{
+				MarkdownDescription: "The processes of the application, ordered by type",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						idKey: guidSchema(),
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The process type",
+							Computed:            true,
+						},
+						"instances": schema.Int64Attribute{
+							MarkdownDescription: "The desired number of instances",
+							Computed:            true,
+						},
+						"running_instances": schema.Int64Attribute{
+							MarkdownDescription: "The number of instances in state RUNNING",
+							Computed:            true,
+						},
+						"memory_in_mb": schema.Int64Attribute{
+							MarkdownDescription: "The memory limit of each instance in MB",
+							Computed:            true,
+						},
+						"disk_in_mb": schema.Int64Attribute{
+							MarkdownDescription: "The disk limit of each instance in MB",
+							Computed:            true,
+						},
+						"instance_stats": schema.ListNestedAttribute{
+							MarkdownDescription: "The runtime state of every instance of the process, ordered by index",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"index": schema.Int64Attribute{
+										MarkdownDescription: "The index of the instance",
+										Computed:            true,
+									},
+									"state": schema.StringAttribute{
+										MarkdownDescription: "The state of the instance, one of RUNNING, CRASHED, STARTING, STOPPING, DOWN",
+										Computed:            true,
+									},
+									"details": schema.StringAttribute{
+										MarkdownDescription: "Information about the state of the instance, e.g. why it crashed or is down",
+										Computed:            true,
+									},
+									"host": schema.StringAttribute{
+										MarkdownDescription: "The host the instance is running on",
+										Computed:            true,
+									},
+									"uptime": schema.Int64Attribute{
+										MarkdownDescription: "The uptime of the instance in seconds",
+										Computed:            true,
+									},
+									"cpu": schema.Float64Attribute{
+										MarkdownDescription: "The current CPU usage of the instance as a fraction of one core",
+										Computed:            true,
+									},
+									"memory_usage": schema.Int64Attribute{
+										MarkdownDescription: "The current memory usage of the instance in bytes",
+										Computed:            true,
+									},
+									"memory_quota": schema.Int64Attribute{
+										MarkdownDescription: "The memory limit of the instance in bytes",
+										Computed:            true,
+									},
+									"disk_usage": schema.Int64Attribute{
+										MarkdownDescription: "The current disk usage of the instance in bytes",
+										Computed:            true,
+									},
+									"disk_quota": schema.Int64Attribute{
+										MarkdownDescription: "The disk limit of the instance in bytes",
+										Computed:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *appProcessesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var data appProcessesDatasourceType
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	processes, err := d.cfClient.Processes.ListForAppAll(ctx, data.App.ValueString(), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Fetching app processes",
+			"Response failed with "+err.Error(),
+		)
+		return
+	}
+
+	stats := make(map[string][]processInstanceStats, len(processes))
+	for _, process := range processes {
+		if !data.Type.IsNull() && process.Type != data.Type.ValueString() {
+			continue
+		}
+		stats[process.GUID], err = getProcessStats(ctx, d.cfClient, process.GUID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"API Error Fetching process stats",
+				fmt.Sprintf("Unable to fetch the stats of process %s: %s", process.Type, err.Error()),
+			)
+			return
+		}
+	}
+
+	data.Processes = mapAppProcessesValuesToType(processes, stats)
+
+	tflog.Trace(ctx, "read the app processes data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
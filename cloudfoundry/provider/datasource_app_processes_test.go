@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAppProcessesDataSource_Configure(t *testing.T) {
+	t.Parallel()
+	dataSourceName := "data.cloudfoundry_app_processes.ds"
+	t.Run("happy path - read web process with instance stats", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_app_processes")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+data "cloudfoundry_app_processes" "ds" {
+	app  = data.cloudfoundry_app.app.id
+	type = "web"
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr(dataSourceName, "processes.#", "1"),
+						resource.TestCheckResourceAttr(dataSourceName, "processes.0.type", "web"),
+						resource.TestCheckResourceAttr(dataSourceName, "processes.0.instance_stats.0.state", "RUNNING"),
+					),
+				},
+			},
+		})
+	})
+	t.Run("error path - read processes of unavailable app", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_app_processes_invalid")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app_processes" "ds" {
+	app = "ec6ac2b3-fb79-43c4-9734-000d4299bd59"
+}
+					`,
+					ExpectError: regexp.MustCompile(`API Error Fetching app processes`),
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &appRevisionsDataSource{}
+var _ datasource.DataSourceWithConfigure = &appRevisionsDataSource{}
+
+func NewAppRevisionsDataSource() datasource.DataSource {
+	return &appRevisionsDataSource{}
+}
+
+type appRevisionsDataSource struct {
+	cfClient *cfv3client.Client
+}
+
+func (d *appRevisionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_revisions"
+}
+
+func (d *appRevisionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	session, ok := req.ProviderData.(*managers.Session)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *managers.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.cfClient = session.CFClient
+}
+
+func (d *appRevisionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Gets information on the revisions of a Cloud Foundry application. A revision can be rolled back to with the `revision` attribute of the `cloudfoundry_app` resource.",
+		Attributes: map[string]schema.Attribute{
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the application",
+				Required:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+			},
+			"deployed": schema.BoolAttribute{
+				MarkdownDescription: "Whether to only list the revisions which are currently deployed",
+				Optional:            true,
+			},
+			"revisions": schema.ListNestedAttribute{
+				MarkdownDescription: "The list of revisions of the application, ordered by version",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						idKey: guidSchema(),
+						"version": schema.Int64Attribute{
+							MarkdownDescription: "Human-readable identifier for the revision, starts at 1 and increments with every new revision of the app",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "A short description of the reason for the revision",
+							Computed:            true,
+						},
+						"droplet": schema.StringAttribute{
+							MarkdownDescription: "The GUID of the droplet associated with the revision",
+							Computed:            true,
+						},
+						"deployable": schema.BoolAttribute{
+							MarkdownDescription: "Whether the revision can be deployed, which is not the case if its droplet is no longer available",
+							Computed:            true,
+						},
+						"deployed": schema.BoolAttribute{
+							MarkdownDescription: "Whether the revision is currently deployed, i.e. running instances of the app use it",
+							Computed:            true,
+						},
+						createdAtKey:   createdAtSchema(),
+						updatedAtKey:   updatedAtSchema(),
+						labelsKey:      datasourceLabelsSchema(),
+						annotationsKey: datasourceAnnotationsSchema(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *appRevisionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var data appRevisionsDatasourceType
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deployedRevisions, err := d.cfClient.Revisions.ListForAppDeployedAll(ctx, data.App.ValueString(), nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Fetching deployed app revisions",
+			"Response failed with "+err.Error(),
+		)
+		return
+	}
+
+	revisions := deployedRevisions
+	if !data.Deployed.ValueBool() {
+		revisions, err = d.cfClient.Revisions.ListForAppAll(ctx, data.App.ValueString(), nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"API Error Fetching app revisions",
+				"Response failed with "+err.Error(),
+			)
+			return
+		}
+	}
+
+	data.Revisions, diags = mapAppRevisionsValuesToType(ctx, revisions, deployedRevisions)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Trace(ctx, "read the app revisions data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAppRevisionsDataSource_Configure(t *testing.T) {
+	t.Parallel()
+	dataSourceName := "data.cloudfoundry_app_revisions.ds"
+	t.Run("happy path - read deployed app revisions", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_app_revisions")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+data "cloudfoundry_app_revisions" "ds" {
+	app      = data.cloudfoundry_app.app.id
+	deployed = true
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr(dataSourceName, "revisions.#", "1"),
+						resource.TestCheckResourceAttr(dataSourceName, "revisions.0.deployed", "true"),
+						resource.TestMatchResourceAttr(dataSourceName, "revisions.0.droplet", regexpValidUUID),
+					),
+				},
+			},
+		})
+	})
+	t.Run("error path - read revisions of unavailable app", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_app_revisions_invalid")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app_revisions" "ds" {
+	app = "ec6ac2b3-fb79-43c4-9734-000d4299bd59"
+}
+					`,
+					ExpectError: regexp.MustCompile(`API Error Fetching deployed app revisions`),
+				},
+			},
+		})
+	})
+}
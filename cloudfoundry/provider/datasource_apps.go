@@ -62,6 +62,15 @@ func (d *appsDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 				MarkdownDescription: "The name of the application to filter by",
 				Optional:            true,
 			},
+			"sensitive_environment_keys": schema.SetAttribute{
+				MarkdownDescription: "The names of the environment variables whose values are redacted in the `environment` of the apps.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"include_features": schema.BoolAttribute{
+				MarkdownDescription: "Whether to read the app features into the `features` of the apps, which takes an additional request per app. Defaults to false.",
+				Optional:            true,
+			},
 			"apps": schema.ListNestedAttribute{
 				MarkdownDescription: "The list of apps",
 				Computed:            true,
@@ -87,6 +96,20 @@ func datasourceAppsSchema() map[string]schema.Attribute {
 			MarkdownDescription: "The name of the associated Cloud Foundry organization to look up",
 			Computed:            true,
 		},
+		"sensitive_environment_keys": schema.SetAttribute{
+			MarkdownDescription: "The names of the environment variables whose values are redacted in `environment`.",
+			Computed:            true,
+			ElementType:         types.StringType,
+		},
+		"include_features": schema.BoolAttribute{
+			MarkdownDescription: "Whether the app features have been read into `features`.",
+			Computed:            true,
+		},
+		"features": schema.MapAttribute{
+			MarkdownDescription: "Whether the 'file-based-vcap-services', 'revisions' and 'service-binding-k8s' app features are enabled, by feature name. Only read if `include_features` is set.",
+			ElementType:         types.BoolType,
+			Computed:            true,
+		},
 		"enable_ssh": schema.BoolAttribute{
 			MarkdownDescription: "Whether SSH access is enabled or disabled on an app level.",
 			Computed:            true,
@@ -124,6 +147,10 @@ func datasourceAppsSchema() map[string]schema.Attribute {
 						MarkdownDescription: "The service instance name.",
 						Computed:            true,
 					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "The name of the service binding.",
+						Computed:            true,
+					},
 					"params": schema.StringAttribute{
 						CustomType:          jsontypes.NormalizedType{},
 						MarkdownDescription: "A json object to represent the parameters for the service instance.",
@@ -293,7 +320,13 @@ func (d *appsDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 			return
 		}
 
-		atResp, diags := mapAppDatasourceValuesToType(ctx, appManifest.Applications[0], app, sshResp)
+		atResp, diags := mapAppDatasourceValuesToType(ctx, appManifest.Applications[0], app, sshResp, data.SensitiveEnvironmentKeys)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		atResp.IncludeFeatures = data.IncludeFeatures
+		atResp.Features, diags = readManagedAppFeatures(ctx, d.cfClient, app.GUID, data.IncludeFeatures)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
 			return
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &tasksDataSource{}
+var _ datasource.DataSourceWithConfigure = &tasksDataSource{}
+
+func NewTasksDataSource() datasource.DataSource {
+	return &tasksDataSource{}
+}
+
+type tasksDataSource struct {
+	cfClient *cfv3client.Client
+}
+
+func (d *tasksDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tasks"
+}
+
+func (d *tasksDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	session, ok := req.ProviderData.(*managers.Session)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *managers.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.cfClient = session.CFClient
+}
+
+func (d *tasksDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Gets information on the tasks which ran or are running on a Cloud Foundry application.",
+		Attributes: map[string]schema.Attribute{
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the application",
+				Required:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the tasks to filter by",
+				Optional:            true,
+			},
+			"states": schema.SetAttribute{
+				MarkdownDescription: "The states of the tasks to filter by, any of 'PENDING', 'RUNNING', 'SUCCEEDED', 'CANCELING' and 'FAILED'",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.OneOf("PENDING", "RUNNING", "SUCCEEDED", "CANCELING", "FAILED")),
+				},
+			},
+			"tasks": schema.ListNestedAttribute{
+				MarkdownDescription: "The list of tasks of the application, oldest first",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						idKey: guidSchema(),
+						"sequence_id": schema.Int64Attribute{
+							MarkdownDescription: "The user-facing id of the task, unique per app",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the task",
+							Computed:            true,
+						},
+						"command": schema.StringAttribute{
+							MarkdownDescription: "The command of the task, only visible to space developers",
+							Computed:            true,
+						},
+						"state": schema.StringAttribute{
+							MarkdownDescription: "The state of the task",
+							Computed:            true,
+						},
+						"memory_in_mb": schema.Int64Attribute{
+							MarkdownDescription: "The memory limit of the task in MB",
+							Computed:            true,
+						},
+						"disk_in_mb": schema.Int64Attribute{
+							MarkdownDescription: "The disk limit of the task in MB",
+							Computed:            true,
+						},
+						"log_rate_limit_per_second": schema.Int64Attribute{
+							MarkdownDescription: "The log rate limit of the task in bytes per second, -1 for unlimited",
+							Computed:            true,
+						},
+						"failure_reason": schema.StringAttribute{
+							MarkdownDescription: "The reason why the task failed",
+							Computed:            true,
+						},
+						"droplet": schema.StringAttribute{
+							MarkdownDescription: "The GUID of the droplet the task ran with",
+							Computed:            true,
+						},
+						createdAtKey: createdAtSchema(),
+						updatedAtKey: updatedAtSchema(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *tasksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	var data tasksDatasourceType
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := url.Values{}
+	if !data.Name.IsNull() {
+		query.Set("names", data.Name.ValueString())
+	}
+	if !data.States.IsNull() {
+		var states []string
+		resp.Diagnostics.Append(data.States.ElementsAs(ctx, &states, false)...)
+		query.Set("states", strings.Join(states, ","))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tasks, err := listTasks(ctx, d.cfClient, data.App.ValueString(), query)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Fetching tasks",
+			"Could not list the tasks of app "+data.App.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+
+	data.Tasks = mapTasksDatasourceValuesToType(tasks)
+
+	tflog.Trace(ctx, "read the tasks data source")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestTasksDataSource_Configure(t *testing.T) {
+	t.Parallel()
+	dataSourceName := "data.cloudfoundry_tasks.tasks"
+	t.Run("happy path - read succeeded tasks of app", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_tasks")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+data "cloudfoundry_tasks" "tasks" {
+	app    = data.cloudfoundry_app.app.id
+	states = ["SUCCEEDED"]
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet(dataSourceName, "tasks.#"),
+						resource.TestCheckResourceAttr(dataSourceName, "tasks.0.state", "SUCCEEDED"),
+						resource.TestMatchResourceAttr(dataSourceName, "tasks.0.id", regexpValidUUID),
+					),
+				},
+			},
+		})
+	})
+	t.Run("error path - read tasks of unavailable app", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/datasource_tasks_invalid")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_tasks" "tasks" {
+	app = "ec6ac2b3-fb79-43c4-9734-000d4299bd59"
+}
+					`,
+					ExpectError: regexp.MustCompile(`API Error Fetching tasks`),
+				},
+			},
+		})
+	})
+}
@@ -321,6 +321,10 @@ func (p *CloudFoundryProvider) Resources(ctx context.Context) []func() resource.
 		NewCFUserResource,
 		NewServicePlanVisibilityResource,
 		NewNetworkPolicyResource,
+		NewPackageResource,
+		NewDropletResource,
+		NewTaskResource,
+		NewAppProcessResource,
 	}
 }
 
@@ -368,6 +372,11 @@ func (p *CloudFoundryProvider) DataSources(ctx context.Context) []func() datasou
 		NewOrgQuotasDataSource,
 		NewSecurityGroupsDataSource,
 		NewStacksDataSource,
+		NewAppRevisionsDataSource,
+		NewAppProcessesDataSource,
+		NewTasksDataSource,
+		NewAppEnvironmentDataSource,
+		NewAppLogsDataSource,
 	}
 }
 
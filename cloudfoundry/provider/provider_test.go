@@ -317,6 +317,10 @@ func TestCloudFoundryProvider_HasResources(t *testing.T) {
 		"cloudfoundry_service_plan_visibility",
 		"cloudfoundry_user_cf",
 		"cloudfoundry_network_policy",
+		"cloudfoundry_package",
+		"cloudfoundry_droplet",
+		"cloudfoundry_task",
+		"cloudfoundry_app_process",
 	}
 
 	ctx := context.Background()
@@ -377,6 +381,11 @@ func TestProvider_HasDataSources(t *testing.T) {
 		"cloudfoundry_org_quotas",
 		"cloudfoundry_security_groups",
 		"cloudfoundry_stacks",
+		"cloudfoundry_app_revisions",
+		"cloudfoundry_app_processes",
+		"cloudfoundry_tasks",
+		"cloudfoundry_app_environment",
+		"cloudfoundry_app_logs",
 	}
 
 	ctx := context.Background()
@@ -1,9 +1,10 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"net/url"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -13,7 +14,10 @@ import (
 	cfv3operation "github.com/cloudfoundry/go-cfclient/v3/operation"
 	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/appbits"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
@@ -34,6 +38,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"gopkg.in/yaml.v2"
 )
 
@@ -48,9 +53,11 @@ const (
 )
 
 var (
-	_ resource.Resource                = &appResource{}
-	_ resource.ResourceWithConfigure   = &appResource{}
-	_ resource.ResourceWithImportState = &appResource{}
+	_ resource.Resource                   = &appResource{}
+	_ resource.ResourceWithConfigure      = &appResource{}
+	_ resource.ResourceWithImportState    = &appResource{}
+	_ resource.ResourceWithValidateConfig = &appResource{}
+	_ resource.ResourceWithModifyPlan     = &appResource{}
 )
 
 func NewAppResource() resource.Resource {
@@ -65,9 +72,9 @@ func (r *appResource) Metadata(_ context.Context, req resource.MetadataRequest,
 	resp.TypeName = req.ProviderTypeName + "_app"
 }
 
-func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *appResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Provides a Cloud Foundry resource to manage applications.",
+		MarkdownDescription: "Provides a Cloud Foundry resource to manage applications. Updates which only change the instances, labels, annotations, features, SSH, environment, routes or sidecars of the app are applied without pushing it, other changes push the app with its `strategy`.",
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				MarkdownDescription: "The name of the application.",
@@ -110,16 +117,17 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Optional: true,
 			},
 			"path": schema.StringAttribute{
-				MarkdownDescription: "The path to the zip file for the application.",
+				MarkdownDescription: "The path to the zip file or the directory of the application. A directory is zipped by the provider, leaving out the files excluded by a `.cfignore` file in it and the files the cf CLI excludes by default such as `.git`. The zip is deterministic, so its hash only changes with the content.",
 				Optional:            true,
 				Validators: []validator.String{
-					stringvalidator.ExactlyOneOf(path.MatchRoot("docker_image"), path.MatchRoot("path")),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("docker_image"), path.MatchRoot("path"), path.MatchRoot("droplet")),
 				},
 			},
 			"source_code_hash": schema.StringAttribute{
 				MarkdownDescription: "Used to trigger updates. Must be set to a base64-encoded SHA256 hash of the path specified.",
 				Optional:            true,
 			},
+			"source_code_digest": sourceCodeDigestSchema(),
 			"docker_image": schema.StringAttribute{
 				MarkdownDescription: "The URL to the docker image with tag e.g registry.example.com:5000/user/repository/tag or docker image name from the public repo e.g. redis:4.0",
 				Optional:            true,
@@ -150,14 +158,112 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 			},
 			"strategy": schema.StringAttribute{
-				MarkdownDescription: "The deployment strategy to use when deploying the application. Valid values are 'none', 'rolling', and 'blue-green', defaults to 'none'.",
+				MarkdownDescription: "The deployment strategy to use when deploying the application. Valid values are 'none', 'rolling', 'blue-green' and 'canary', defaults to 'none'. The 'canary' strategy is applied on updates of a started app, the initial deployment is done without a strategy.",
 				Optional:            true,
 				Validators: []validator.String{
-					stringvalidator.OneOf("none", "rolling", "blue-green"),
+					stringvalidator.OneOf("none", "rolling", "blue-green", "canary"),
 				},
 			},
+			"canary_steps": schema.ListNestedAttribute{
+				MarkdownDescription: "The steps of a deployment with 'canary' strategy. At each step the given percentage of instances is moved to the new version, and the deployment is continued once the pause has elapsed and the new instances are healthy. If a step fails its health check the deployment is cancelled. Without steps, CF pauses once after the first canary instance. Used only when strategy is set to 'canary'.",
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"instance_weight": schema.Int64Attribute{
+							MarkdownDescription: "The percentage of instances running the new version once the step is reached. Must be greater than the weight of the previous step.",
+							Required:            true,
+							Validators: []validator.Int64{
+								int64validator.Between(1, 100),
+							},
+						},
+						"pause_duration": schema.Int64Attribute{
+							MarkdownDescription: "Time in seconds to wait once the step is reached before the deployment is continued. Defaults to 0.",
+							Optional:            true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+							},
+						},
+						"health_check": schema.BoolAttribute{
+							MarkdownDescription: "Whether all instances of the new version must be running before the deployment is continued. Defaults to true.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"droplet": schema.StringAttribute{
+				MarkdownDescription: "The GUID of a staged droplet to deploy instead of pushing `path` or `docker_image`, e.g. a `cloudfoundry_droplet` staged once and promoted to the app in every environment. A droplet of another app, also in another space, is copied to the app first, so that it runs the exact same bits. The droplet is deployed with the configured strategy whenever the app is updated, 'blue-green' as a rolling deployment.",
+				Optional:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+			},
+			"resource_matching": schema.BoolAttribute{
+				MarkdownDescription: "Whether to upload only the files of `path` which the Cloud Controller does not already have in its resource cache. The SHA1 checksum of every file is matched against the cache first and matched files are left out of the uploaded archive; if matching or the partial upload fails, the full archive is uploaded instead. The app is then staged and deployed without the push operation, an existing app using the 'blue-green' strategy gets a rolling deployment instead. Defaults to false.",
+				Optional:            true,
+				Validators: []validator.Bool{
+					boolvalidator.ConflictsWith(path.MatchRoot("docker_image"), path.MatchRoot("droplet")),
+				},
+			},
+			"revision": schema.StringAttribute{
+				MarkdownDescription: "The GUID of a revision of the app to deploy instead of pushing the bits, e.g. to roll back to a previous version. Revisions can be looked up with the `cloudfoundry_app_revisions` data source. The revision is deployed through the deployments API whenever this value changes on an existing app, honouring the 'canary' strategy; any other strategy results in a rolling deployment. Other changes are applied with a regular push while it is unchanged.",
+				Optional:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+			},
+			"rollback_on_failure": schema.BoolAttribute{
+				MarkdownDescription: "Whether to roll back an existing app to the droplet it was running before the update if staging or the deployment fails. The apply waits until the previous version is healthy again and then reports the original error. Defaults to false.",
+				Optional:            true,
+			},
+			"logging": schema.SingleNestedAttribute{
+				MarkdownDescription: "Tails the staging and app logs from log-cache while the app is pushed. The log lines are forwarded to the Terraform logs and the last lines are included in the error if the push fails.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"stream": schema.BoolAttribute{
+						MarkdownDescription: "Whether to forward the log lines to the Terraform logs at INFO level while the push runs. Defaults to true.",
+						Optional:            true,
+					},
+					"source_types": schema.SetAttribute{
+						MarkdownDescription: "The log source types to include, e.g. `STG` for staging and `APP` for the app instances. A source type matches all source types starting with it, so `APP` includes `APP/PROC/WEB`. Defaults to all source types.",
+						Optional:            true,
+						ElementType:         types.StringType,
+						Validators: []validator.Set{
+							setvalidator.SizeAtLeast(1),
+						},
+					},
+					"tail_lines": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("The number of last OUT and ERR log lines included in the error if the push fails. Defaults to %d.", defaultLogTailLines),
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"log_cache_url": schema.StringAttribute{
+						MarkdownDescription: "The URL of log-cache. Defaults to the API URL with its `api.` host prefix replaced by `log-cache.`.",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+				},
+			},
+			"features": schema.MapAttribute{
+				MarkdownDescription: "The app features to enable or disable, by feature name. Valid names are 'revisions' and 'service-binding-k8s'; SSH is managed with `enable_ssh` and file-based VCAP services with `file_based_vcap_services`. Only the configured features are managed and checked for drift. Changing 'service-binding-k8s' restarts the app.",
+				Optional:            true,
+				ElementType:         types.BoolType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.OneOf(configurableAppFeatures...)),
+				},
+			},
+			"file_based_vcap_services": schema.BoolAttribute{
+				MarkdownDescription: "Whether the service bindings are provided to the app as a file referenced by the `VCAP_SERVICES_FILE_PATH` environment variable instead of the `VCAP_SERVICES` environment variable, which is limited in size. Changing it restarts the app.",
+				Optional:            true,
+			},
 			"service_bindings": schema.SetNestedAttribute{
-				MarkdownDescription: "Service instances to bind to the application.",
+				MarkdownDescription: "Service instances to bind to the application. Changes are applied in place: bindings with changed parameters or name are deleted before the app is pushed, which creates the new bindings and restages the app using the configured strategy. If the push fails, the changed bindings are restored. Removed bindings are deleted once the push succeeded.",
 				Optional:            true,
 				Computed:            true,
 				Validators: []validator.Set{
@@ -165,7 +271,6 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 					setvalidator.AlsoRequires(path.MatchRoot("service_bindings").AtAnySetValue().AtName("service_instance")),
 				},
 				PlanModifiers: []planmodifier.Set{
-					setplanmodifier.RequiresReplace(),
 					setplanmodifier.UseStateForUnknown(),
 				},
 				NestedObject: schema.NestedAttributeObject{
@@ -175,6 +280,13 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 							Optional:            true,
 							Computed:            true,
 						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the service binding, it is used instead of the service instance name to look up the credentials in VCAP_SERVICES or the binding files.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
 						"params": schema.StringAttribute{
 							CustomType:          jsontypes.NormalizedType{},
 							MarkdownDescription: "A json object to send to the service broker during service binding.",
@@ -186,16 +298,13 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 			},
 			"routes": schema.SetNestedAttribute{
-				MarkdownDescription: "The routes to map to the application to control its ingress traffic.",
+				MarkdownDescription: "The routes to map to the application to control its ingress traffic. Changes are applied in place once the app has been updated: new routes are mapped, and created first if they do not exist, and removed routes are unmapped from the app. The routes themselves are not deleted.",
 				Optional:            true,
 				Validators: []validator.Set{
 					setvalidator.SizeAtLeast(1),
 					setvalidator.AlsoRequires(path.MatchRoot("routes").AtAnySetValue().AtName("route")),
 				},
 				Computed: true,
-				PlanModifiers: []planmodifier.Set{
-					setplanmodifier.RequiresReplaceIfConfigured(),
-				},
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"route": schema.StringAttribute{
@@ -231,6 +340,22 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 				ElementType: types.StringType,
 			},
+			"sensitive_environment": schema.MapAttribute{
+				MarkdownDescription: "Key/value pairs of sensitive environment variables to set in your app, such as passwords and API keys. The values are not shown in the plan output and drift is detected by comparing digests of the values. The keys must not be set in `environment` as well.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+				},
+				ElementType: types.StringType,
+			},
+			"environment_update_action": schema.StringAttribute{
+				MarkdownDescription: "The action which applies changed `environment` or `sensitive_environment` variables to a started app after an update. Valid values are 'restart', 'restage' and 'none', defaults to 'none', with which the new variables are picked up on the next restart. The action honours `strategy`: apps using 'rolling' or 'blue-green' get a rolling deployment and 'canary' apps a canary deployment, so that they pick up the new environment without downtime. A 'restage' stages the current package again first. Updates which push the app already apply the new environment with the push and are not restarted again.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(environmentUpdateRestart, environmentUpdateRestage, environmentUpdateNone),
+				},
+			},
 			"no_route": schema.BoolAttribute{
 				MarkdownDescription: "The attribute with a value of true to prevent a route from being created for your app.",
 				Optional:            true,
@@ -248,20 +373,40 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 			},
 			"app_deployed_running_timeout": schema.Int64Attribute{
-				MarkdownDescription: "Timeout in minutes to wait for app to be running after updating deployment with 'blue-green' strategy. The default is 5 minutes. Min value is 1 minute. Used only when strategy is set to 'blue-green'.",
+				MarkdownDescription: "Timeout in minutes to wait for app to be running after updating deployment with 'blue-green' strategy. The default is 5 minutes. Min value is 1 minute. Used only when strategy is set to 'blue-green' or 'canary', or when `wait_for_healthy` is set. For 'canary' and `wait_for_healthy` it defaults to 20 minutes; for 'canary' it bounds the whole deployment excluding the step pauses.",
 				Optional:            true,
 				Validators: []validator.Int64{
 					int64validator.AtLeast(AppDeployedRunningTimeoutMinutesMinimum),
 				},
 			},
 			"app_deployed_running_check_interval": schema.Int64Attribute{
-				MarkdownDescription: "The interval in seconds between checks to see if the app is running after updating deployment with 'blue-green' strategy. The default is 5 seconds. Min value is 1 second, max value is 30 seconds. Used only when strategy is set to 'blue-green'.",
+				MarkdownDescription: "The interval in seconds between checks to see if the app is running after updating deployment with 'blue-green' or 'canary' strategy. The default is 5 seconds. Min value is 1 second, max value is 30 seconds. Used only when strategy is set to 'blue-green' or 'canary', or when `wait_for_healthy` is set.",
 				Optional:            true,
 				Validators: []validator.Int64{
 					int64validator.AtLeast(AppDeployedRunningCheckIntervalSecondsMinimum),
 					int64validator.AtMost(AppDeployedRunningCheckIntervalSecondsMaximum),
 				},
 			},
+			"wait_for_healthy": schema.BoolAttribute{
+				MarkdownDescription: "Whether to wait after the app has been pushed with any strategy until the desired number of instances of every process is running. The process stats are polled every `app_deployed_running_check_interval` seconds for up to `app_deployed_running_timeout` minutes; if the instances do not get there, the apply fails with the crash reasons of the instances. Defaults to false.",
+				Optional:            true,
+			},
+			"ignore_instances_drift": schema.BoolAttribute{
+				MarkdownDescription: "Whether to leave the instances of the processes to others, e.g. an autoscaler or `cloudfoundry_app_process`. The configured instances are only used to create the app or when they are changed; updating the app keeps the current instances and instance counts changed outside of Terraform are not shown as drift. Defaults to false.",
+				Optional:            true,
+			},
+			"ignore_process_drift": schema.BoolAttribute{
+				MarkdownDescription: "Whether to leave the scale and the health checks of all processes to `cloudfoundry_app_process`. The instances, memory, disk quota, log rate limit and health check settings can then not be configured on the app, they are not pushed with the app and changes of them are not shown as drift. Defaults to false.",
+				Optional:            true,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "Timeout for creating the app, including staging and waiting for it to be healthy. By default the creation is not bounded",
+				Update:            true,
+				UpdateDescription: "Timeout for updating the app, including staging and waiting for it to be healthy. By default the update is not bounded",
+				Delete:            true,
+				DeleteDescription: "Timeout for deleting the app. Default is 20 minutes",
+			}),
 			"processes": schema.SetNestedAttribute{
 				MarkdownDescription: "List of configurations for individual process types.",
 				Optional:            true,
@@ -287,14 +432,11 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 			},
 			"sidecars": schema.SetNestedAttribute{
-				MarkdownDescription: "The attribute specifies additional processes to run in the same container as your app",
+				MarkdownDescription: "The attribute specifies additional processes to run in the same container as your app. Changes are applied in place with the sidecars API: sidecars are created, updated or deleted by name and a started app is restarted using the configured strategy.",
 				Optional:            true,
 				Validators: []validator.Set{
 					setvalidator.SizeAtLeast(1),
 				},
-				PlanModifiers: []planmodifier.Set{
-					setplanmodifier.RequiresReplace(),
-				},
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"name": schema.StringAttribute{
@@ -441,7 +583,96 @@ func (r *appResource) Configure(ctx context.Context, req resource.ConfigureReque
 	r.cfClient = session.CFClient
 }
 
+func (r *appResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var (
+		strategy           types.String
+		canarySteps        types.List
+		steps              []CanaryStep
+		environment        types.Map
+		sensitiveEnv       types.Map
+		ignoreProcessDrift types.Bool
+	)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("strategy"), &strategy)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("canary_steps"), &canarySteps)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("environment"), &environment)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sensitive_environment"), &sensitiveEnv)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ignore_process_drift"), &ignoreProcessDrift)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if ignoreProcessDrift.ValueBool() {
+		resp.Diagnostics.Append(validateUnmanagedProcessSettings(ctx, req.Config)...)
+	}
+	for key := range sensitiveEnv.Elements() {
+		if _, ok := environment.Elements()[key]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("sensitive_environment"),
+				"Invalid attribute combination",
+				fmt.Sprintf("environment variable %q is set in both environment and sensitive_environment", key),
+			)
+		}
+	}
+	if canarySteps.IsNull() || canarySteps.IsUnknown() {
+		return
+	}
+	if !strategy.IsUnknown() && strategy.ValueString() != deploymentStrategyCanary {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("canary_steps"),
+			"Invalid attribute combination",
+			"canary_steps can only be set when strategy is 'canary'",
+		)
+		return
+	}
+	resp.Diagnostics.Append(canarySteps.ElementsAs(ctx, &steps, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateCanarySteps(steps)...)
+}
+
+func (r *appResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanSourceCodeDigest(ctx, req, resp)
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	// only the attributes used below are read, as the plan may hold unknown nested values
+	var planned, previous AppType
+	for attribute, target := range map[string]any{
+		"name":                      &planned.Name,
+		"strategy":                  &planned.Strategy,
+		"stopped":                   &planned.Stopped,
+		"environment_update_action": &planned.EnvironmentUpdateAction,
+		"environment":               &planned.Environment,
+		"sensitive_environment":     &planned.SensitiveEnvironment,
+	} {
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(attribute), target)...)
+	}
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("environment"), &previous.Environment)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("sensitive_environment"), &previous.SensitiveEnvironment)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if message := environmentUpdateMessage(planned); message != "" && environmentChanged(previous, planned) {
+		resp.Diagnostics.AddWarning("App will be restarted", message)
+	}
+}
+
 func (r *appResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plannedTimeouts timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &plannedTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// the app is only bounded by a deadline if one is configured, canary deployments may pause for long
+	createTimeout, diags := plannedTimeouts.Create(ctx, 0)
+	if errors := diags.Errors(); len(errors) > 0 {
+		tflog.Warn(ctx, "reading configured create timeout", map[string]interface{}{
+			"summary": errors[0].Summary(),
+			"detail":  errors[0].Detail(),
+		})
+	}
+	ctx, cancel := contextWithOptionalTimeout(ctx, createTimeout)
+	defer cancel()
 	r.upsert(ctx, &req.Plan, nil, &resp.State, &resp.Diagnostics)
 }
 
@@ -481,6 +712,16 @@ func (r *appResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	plan, diags := mapAppValuesToType(ctx, appManifest.Applications[0], appResp, &appType, sshResp)
 	resp.Diagnostics.Append(diags...)
 	plan.CopyConfigAttributes(&appType)
+	plan.keepConfiguredInstances(&appType)
+	plan.keepUnmanagedProcessSettings(&appType)
+	resp.Diagnostics.Append(r.readAppFeatures(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.SourceCodeDigest.IsNull() {
+		// adopt the current content for states written before the digest was tracked, instead of planning a push
+		plan.SourceCodeDigest = sourceCodeDigestValue(ctx, types.StringUnknown(), plan.Path)
+	}
 	plan.Space = types.StringValue(space.Name)
 	plan.Org = types.StringValue(org.Name)
 	if plan.Stopped.IsNull() || plan.Stopped.IsUnknown() {
@@ -490,6 +731,20 @@ func (r *appResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 }
 
 func (r *appResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plannedTimeouts timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &plannedTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	updateTimeout, diags := plannedTimeouts.Update(ctx, 0)
+	if errors := diags.Errors(); len(errors) > 0 {
+		tflog.Warn(ctx, "reading configured update timeout", map[string]interface{}{
+			"summary": errors[0].Summary(),
+			"detail":  errors[0].Detail(),
+		})
+	}
+	ctx, cancel := contextWithOptionalTimeout(ctx, updateTimeout)
+	defer cancel()
 	r.upsert(ctx, &req.Plan, &req.State, &resp.State, &resp.Diagnostics)
 }
 func (r *appResource) upsert(ctx context.Context, reqPlan *tfsdk.Plan, reqState *tfsdk.State, respState *tfsdk.State, respDiags *diag.Diagnostics) {
@@ -503,6 +758,7 @@ func (r *appResource) upsert(ctx context.Context, reqPlan *tfsdk.Plan, reqState
 	if respDiags.HasError() {
 		return
 	}
+	desiredState.SourceCodeDigest = sourceCodeDigestValue(ctx, desiredState.SourceCodeDigest, desiredState.Path)
 	appManifestValue, diags := desiredState.mapAppTypeToValues(ctx)
 	respDiags.Append(diags...)
 	if respDiags.HasError() {
@@ -517,25 +773,191 @@ func (r *appResource) upsert(ctx context.Context, reqPlan *tfsdk.Plan, reqState
 		respDiags.Append(diags...)
 		appManifestValue.Metadata, diags = setClientMetadataForUpdate(ctx, previousState.Labels, previousState.Annotations, desiredState.Labels, desiredState.Annotations)
 		respDiags.Append(diags...)
-		envs, diags = setEnvForUpdate(ctx, previousState.Environment, desiredState.Environment)
+		envs, diags = setEnvForUpdate(ctx, previousState.Environment, previousState.SensitiveEnvironment, desiredState.Environment, desiredState.SensitiveEnvironment)
 		respDiags.Append(diags...)
+		if desiredState.IgnoreInstancesDrift.ValueBool() {
+			err := r.keepCurrentInstances(ctx, previousState.ID.ValueString(), appManifestValue, &previousState, &desiredState)
+			if err != nil {
+				respDiags.AddError("Error reading current instances of the app", err.Error())
+				return
+			}
+		}
+	}
+
+	desiredFeatures, diags := desiredAppFeatures(ctx, desiredState)
+	respDiags.Append(diags...)
+	if respDiags.HasError() {
+		return
+	}
+
+	var (
+		restart                          bool
+		removedBindings, changedBindings []ServiceBinding
+		mappedRoutes                     []Route
+		unmappedRoutes                   []string
+		err                              error
+	)
+	if reqState != nil {
+		var previousBindings, desiredBindings []ServiceBinding
+		if !previousState.ServiceBindings.IsNull() && !previousState.ServiceBindings.IsUnknown() {
+			respDiags.Append(previousState.ServiceBindings.ElementsAs(ctx, &previousBindings, false)...)
+		}
+		if !desiredState.ServiceBindings.IsNull() && !desiredState.ServiceBindings.IsUnknown() {
+			respDiags.Append(desiredState.ServiceBindings.ElementsAs(ctx, &desiredBindings, false)...)
+		}
+		if respDiags.HasError() {
+			return
+		}
+		// changed bindings are bound again by the push, removed ones are only deleted once the push succeeded
+		removedBindings, changedBindings = obsoleteServiceBindings(previousBindings, desiredBindings)
+		if len(changedBindings) > 0 {
+			if err := r.unbindServices(ctx, previousState.ID.ValueString(), changedBindings); err != nil {
+				respDiags.AddError("Error deleting service bindings", err.Error())
+				return
+			}
+		}
+		// routes are mapped once the push succeeded and sidecars are applied with their own API, so the push leaves
+		// both of them untouched
+		if !desiredState.Routes.IsUnknown() {
+			previousRoutes, diags := routesFromSet(ctx, previousState.Routes)
+			respDiags.Append(diags...)
+			desiredRoutes, diags := routesFromSet(ctx, desiredState.Routes)
+			respDiags.Append(diags...)
+			if respDiags.HasError() {
+				return
+			}
+			mappedRoutes, unmappedRoutes = routeChanges(previousRoutes, desiredRoutes)
+			appManifestValue.Routes = nil
+		}
+		restart, err = r.updateSidecars(ctx, previousState.ID.ValueString(), previousState.Sidecars, desiredState.Sidecars)
+		if err != nil {
+			respDiags.AddError("Error updating sidecars", err.Error())
+			return
+		}
+		appManifestValue.Sidecars = nil
+		featuresRestart, err := r.updateAppFeatures(ctx, previousState.ID.ValueString(), desiredFeatures)
+		if err != nil {
+			respDiags.AddError("Error setting app features", err.Error())
+			return
+		}
+		restart = restart || featuresRestart
+	}
+
+	revisionChanged := reqState != nil && !desiredState.Revision.IsNull() && !desiredState.Revision.Equal(previousState.Revision)
+	inPlace := reqState != nil && !revisionChanged && !appUpdateRequiresPush(&previousState, &desiredState)
+
+	var previousDropletGUID string
+	if reqState != nil && !inPlace && desiredState.RollbackOnFailure.ValueBool() {
+		droplet, err := r.cfClient.Droplets.GetCurrentForApp(ctx, previousState.ID.ValueString())
+		if err != nil && !cfv3resource.IsResourceNotFoundError(err) {
+			respDiags.AddError("Error reading current droplet of app", err.Error())
+			return
+		}
+		if droplet != nil {
+			previousDropletGUID = droplet.GUID
+		}
 	}
 
+	// a push applies the environment itself, an in place update restarts the app once for its environment update action
+	// after the variables are set, which also applies the features and sidecars which require a restart
+	updateEnvironment := inPlace && environmentChanged(previousState, desiredState) && environmentUpdateRestarts(desiredState)
+
 	curTime := time.Now()
-	appResp, err := r.push(desiredState, appManifestValue, ctx)
+	var logStreamer *appLogStreamer
+	if desiredState.Logging != nil {
+		logStreamer = r.startAppLogStreamer(ctx, desiredState, previousState.ID.ValueString(), curTime)
+	}
+	var appResp *cfv3resource.App
+	switch {
+	case revisionChanged:
+		appResp, err = r.deployRevision(ctx, previousState.ID.ValueString(), desiredState)
+	case inPlace:
+		appResp, err = r.updateInPlace(ctx, &previousState, &desiredState, appManifestValue.Metadata, restart && !updateEnvironment)
+	default:
+		appResp, err = r.push(desiredState, appManifestValue, ctx)
+	}
+	if err == nil && desiredState.WaitForHealthy.ValueBool() && !desiredState.Stopped.ValueBool() {
+		err = r.waitForHealthyInstances(ctx, appResp.GUID, desiredState, curTime)
+	}
 
+	var logLines []string
+	if logStreamer != nil {
+		logLines = logStreamer.stop(ctx)
+	}
 	if err != nil {
-
-		errString := getAppLogTrace(ctx, r, desiredState, curTime)
+		// the push may have failed because the context expired, looking up the logs and restoring the previous state
+		// must still reach the API. The rollback waits for a deployment and then for the app to be healthy.
+		deploymentTimeout, _ := deploymentTimeouts(desiredState)
+		restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultTimeout+2*deploymentTimeout)
+		defer cancel()
+		var errString []string
+		if logStreamer != nil {
+			if len(logLines) > 0 {
+				errString = append(errString, "Last app log lines:\n")
+			}
+			for _, line := range logLines {
+				errString = append(errString, line+"\n")
+			}
+		} else {
+			errString = getAppLogTrace(restoreCtx, r, desiredState, curTime)
+		}
+		if len(changedBindings) > 0 {
+			bindErr := r.bindServices(restoreCtx, previousState.ID.ValueString(), changedBindings)
+			if bindErr != nil {
+				errString = append(errString, "Restoring the previous service bindings failed: "+bindErr.Error()+"\n")
+			} else {
+				errString = append(errString, "The previous service bindings have been restored.\n")
+			}
+		}
+		if previousDropletGUID != "" {
+			rollbackErr := r.rollback(restoreCtx, previousState.ID.ValueString(), previousDropletGUID, desiredState)
+			if rollbackErr != nil {
+				errString = append(errString, "Rollback to droplet "+previousDropletGUID+" failed: "+rollbackErr.Error()+"\n")
+			} else {
+				errString = append(errString, "The app has been rolled back to droplet "+previousDropletGUID+".\n")
+			}
+		}
 		respDiags.AddError("Error pushing app", err.Error()+"\n"+strings.Join(errString, ""))
 		return
 	}
 
+	if len(removedBindings) > 0 {
+		if err := r.unbindServices(ctx, appResp.GUID, removedBindings); err != nil {
+			respDiags.AddError("Error deleting service bindings", err.Error())
+			return
+		}
+	}
+
+	if len(unmappedRoutes) > 0 || len(mappedRoutes) > 0 {
+		if err := r.updateRoutes(ctx, appResp.GUID, unmappedRoutes, mappedRoutes); err != nil {
+			respDiags.AddError("Error updating routes", err.Error())
+			return
+		}
+	}
+
+	if reqState == nil {
+		restart, err := r.updateAppFeatures(ctx, appResp.GUID, desiredFeatures)
+		if err == nil && restart && !desiredState.Stopped.ValueBool() {
+			appResp, err = r.cfClient.Applications.Restart(ctx, appResp.GUID)
+		}
+		if err != nil {
+			respDiags.AddError("Error setting app features", err.Error())
+			return
+		}
+	}
+
 	_, err = r.cfClient.Applications.SetEnvironmentVariables(ctx, appResp.GUID, envs)
 	if err != nil {
 		respDiags.AddError("Error setting environment variables", err.Error())
 		return
 	}
+	if updateEnvironment {
+		err = r.applyEnvironmentUpdate(ctx, appResp.GUID, desiredState)
+		if err != nil {
+			respDiags.AddError("Error applying environment variables", err.Error())
+			return
+		}
+	}
 
 	sshResp, err := r.cfClient.AppFeatures.UpdateSSH(ctx, appResp.GUID, desiredState.EnableSSH.ValueBool())
 	if err != nil {
@@ -546,26 +968,51 @@ func (r *appResource) upsert(ctx context.Context, reqPlan *tfsdk.Plan, reqState
 	manifestRespRaw, err := r.cfClient.Manifests.Generate(ctx, appResp.GUID)
 	if err != nil {
 		respDiags.AddError("Error generating manifest", err.Error())
+		return
 	}
 	var manifest *cfv3operation.Manifest
 	err = yaml.Unmarshal([]byte(manifestRespRaw), &manifest)
 	if err != nil {
 		respDiags.AddError("Error unmarshalling manifest", err.Error())
+		return
 	}
 	plan, diags := mapAppValuesToType(ctx, manifest.Applications[0], appResp, &desiredState, sshResp)
 	respDiags.Append(diags...)
 	plan.CopyConfigAttributes(&desiredState)
+	plan.keepConfiguredInstances(&desiredState)
+	plan.keepUnmanagedProcessSettings(&desiredState)
 	plan.Stopped = desiredState.Stopped
 	respDiags.Append(respState.Set(ctx, &plan)...)
 }
 func (r *appResource) push(appType AppType, appManifestValue *cfv3operation.AppManifest, ctx context.Context) (*cfv3resource.App, error) {
-	var file *os.File
-	var err error
+	resourceMatching := appType.ResourceMatching.ValueBool() && !appType.Path.IsNull()
+	canary := appType.Strategy.ValueString() == deploymentStrategyCanary && !appType.Stopped.ValueBool()
+	droplet := !appType.Droplet.IsNull()
+	var (
+		app   *cfv3resource.App
+		space *cfv3resource.Space
+	)
+	if resourceMatching || canary || droplet {
+		var err error
+		app, space, err = r.findApp(ctx, appType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resourceMatching || droplet {
+		return r.pushStaged(ctx, app, space.GUID, appType, appManifestValue, nil)
+	}
+	var bits io.Reader
 	if !appType.Path.IsNull() {
-		file, err = os.Open(appType.Path.ValueString())
+		appBits, err := openAppBits(appType.Path.ValueString())
 		if err != nil {
 			return nil, err
 		}
+		defer appBits.Close()
+		bits = appBits
+	}
+	if canary && app != nil {
+		return r.pushStaged(ctx, app, space.GUID, appType, appManifestValue, bits)
 	}
 	manifestOp := cfv3operation.NewAppPushOperation(r.cfClient, appType.Org.ValueString(), appType.Space.ValueString())
 	if !appType.Strategy.IsNull() {
@@ -582,13 +1029,29 @@ func (r *appResource) push(appType AppType, appManifestValue *cfv3operation.AppM
 	if appType.Stopped.ValueBool() {
 		manifestOp.WithNoStart(true)
 	}
-	appResp, err := manifestOp.Push(ctx, appManifestValue, file)
+	appResp, err := manifestOp.Push(ctx, appManifestValue, bits)
 	if err != nil {
 		return nil, err
 	}
 	return appResp, nil
 }
 
+// openAppBits opens the zip archive at the app path, or zips the directory at the app path in-memory.
+func openAppBits(appPath string) (io.ReadCloser, error) {
+	isDir, err := appbits.IsDirectory(appPath)
+	if err != nil {
+		return nil, err
+	}
+	if !isDir {
+		return os.Open(appPath)
+	}
+	data, err := appbits.ZipDirectory(appPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to zip app directory %s: %w", appPath, err)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
 func (r *appResource) getBlueGreenDeploymentStrategyOptions(appType AppType) (uint, uint) {
 	if appType.AppDeployedRunningTimeout.IsNull() && appType.AppDeployedRunningCheckInterval.IsNull() {
 		return AppDeployedRunningTimeoutMinutesFeatureNotUsed, AppDeployedRunningCheckIntervalSecondsFeatureNotUsed
@@ -611,6 +1074,13 @@ func (r *appResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	deleteTimeout, diags := appType.Timeouts.Delete(ctx, defaultTimeout)
+	if errors := diags.Errors(); len(errors) > 0 {
+		tflog.Warn(ctx, "reading configured delete timeout", map[string]interface{}{
+			"summary": errors[0].Summary(),
+			"detail":  errors[0].Detail(),
+		})
+	}
 	jobID, err := r.cfClient.Applications.Delete(ctx, appType.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -619,7 +1089,7 @@ func (r *appResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		)
 		return
 	}
-	err = pollJob(ctx, *r.cfClient, jobID, defaultTimeout)
+	err = pollJob(ctx, *r.cfClient, jobID, deleteTimeout)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to verify org quota deletion",
@@ -637,7 +1107,6 @@ func getAppLogTrace(ctx context.Context, r *appResource, desiredState AppType, c
 
 	EnableCFAppLogTrace := os.Getenv("ENABLE_CF_APP_LOG_TRACE")
 	var errString []string
-	var logCacheAddr string
 	if EnableCFAppLogTrace == "true" {
 
 		org, _ := r.cfClient.Organizations.Single(ctx, &cfv3client.OrganizationListOptions{
@@ -665,17 +1134,11 @@ func getAppLogTrace(ctx context.Context, r *appResource, desiredState AppType, c
 			},
 		})
 
-		apiURL := app.Links.Self().Href
-		parsedURL, err := url.Parse(apiURL)
+		logCacheAddr, err := logCacheURL(app.Links.Self().Href, "")
 		if err != nil {
 			errString = append(errString, "Error in getting cf log: "+err.Error())
 			return errString
 		}
-		host := parsedURL.Host
-		if strings.HasPrefix(host, "api.") {
-			logCacheAddr = strings.Replace(host, "api.", "log-cache.", 1)
-			logCacheAddr = "https://" + logCacheAddr
-		}
 
 		log_client := logclient.NewClient(logCacheAddr, logclient.WithHTTPClient(r.cfClient.HTTPAuthClient()))
 		es, err := log_client.Read(ctx, app.GUID, curTime)
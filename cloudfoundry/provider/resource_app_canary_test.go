@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCanarySteps(t *testing.T) {
+	step := func(weight int64) CanaryStep {
+		return CanaryStep{InstanceWeight: types.Int64Value(weight)}
+	}
+
+	t.Run("increasing weights are valid", func(t *testing.T) {
+		diags := validateCanarySteps([]CanaryStep{step(10), step(50), step(100)})
+		assert.False(t, diags.HasError())
+	})
+
+	t.Run("unknown weights are skipped", func(t *testing.T) {
+		diags := validateCanarySteps([]CanaryStep{step(50), {InstanceWeight: types.Int64Unknown()}, step(10)})
+		assert.False(t, diags.HasError())
+	})
+
+	t.Run("equal or decreasing weights are invalid", func(t *testing.T) {
+		diags := validateCanarySteps([]CanaryStep{step(20), step(20), step(10)})
+		assert.Equal(t, 2, diags.ErrorsCount())
+		assert.Contains(t, diags.Errors()[0].Detail(), "instance_weight of step 2 must be greater than 20")
+	})
+}
+
+func TestUnhealthyInstancesError(t *testing.T) {
+	assert.NoError(t, unhealthyInstancesError("web", []cfv3resource.ProcessStat{
+		{Index: 0, State: "RUNNING"},
+		{Index: 1, State: "RUNNING"},
+	}))
+
+	err := unhealthyInstancesError("web", []cfv3resource.ProcessStat{
+		{Index: 0, State: "RUNNING"},
+		{Index: 1, State: "CRASHED"},
+		{Index: 2, State: "STARTING"},
+	})
+	assert.EqualError(t, err, "process web is not healthy: instance 1 is CRASHED, instance 2 is STARTING")
+}
+
+func TestCanaryDeploymentTimeouts(t *testing.T) {
+	timeout, checkInterval := canaryDeploymentTimeouts(AppType{})
+	assert.Equal(t, defaultTimeout, timeout)
+	assert.Equal(t, AppDeployedRunningCheckIntervalSecondsDefault*time.Second, checkInterval)
+
+	timeout, checkInterval = canaryDeploymentTimeouts(AppType{
+		AppDeployedRunningTimeout:       types.Int64Value(2),
+		AppDeployedRunningCheckInterval: types.Int64Value(10),
+		CanarySteps: []CanaryStep{
+			{InstanceWeight: types.Int64Value(10), PauseDuration: types.Int64Value(60)},
+			{InstanceWeight: types.Int64Value(50)},
+		},
+	})
+	assert.Equal(t, 3*time.Minute, timeout)
+	assert.Equal(t, 10*time.Second, checkInterval)
+}
+
+func TestCrashReasons(t *testing.T) {
+	crash := func(processType string, index int, at int, description string) appCrashEvent {
+		var event appCrashEvent
+		event.CreatedAt = time.Date(2024, 5, 1, 10, at, 0, 0, time.UTC)
+		event.Data.ProcessType = processType
+		event.Data.Index = index
+		event.Data.Reason = "CRASHED"
+		event.Data.ExitDescription = description
+		return event
+	}
+	reasons := crashReasons([]appCrashEvent{
+		crash("web", 1, 3, "Exited with status 1"),
+		crash("worker", 0, 2, "out of memory"),
+		crash("web", 1, 1, "older crash"),
+		crash("", 0, 0, "failed health check"),
+	})
+	assert.Equal(t, []string{
+		"instance 0 of process web crashed at 2024-05-01T10:00:00Z: failed health check (CRASHED)",
+		"instance 1 of process web crashed at 2024-05-01T10:03:00Z: Exited with status 1 (CRASHED)",
+		"instance 0 of process worker crashed at 2024-05-01T10:02:00Z: out of memory (CRASHED)",
+	}, reasons)
+	assert.Empty(t, crashReasons(nil))
+}
@@ -0,0 +1,292 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                = &appProcessResource{}
+	_ resource.ResourceWithConfigure   = &appProcessResource{}
+	_ resource.ResourceWithImportState = &appProcessResource{}
+)
+
+// Instantiates an app process resource.
+func NewAppProcessResource() resource.Resource {
+	return &appProcessResource{}
+}
+
+// Contains reference to the v3 client to be used for making the API calls.
+type appProcessResource struct {
+	cfClient *cfv3client.Client
+}
+
+func (r *appProcessResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_process"
+}
+
+func (r *appProcessResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the scale and the health checks of one process type of an app through the processes API, without pushing the app. Settings which are not configured are left as they are, e.g. to an autoscaler. The settings should not be configured on the `cloudfoundry_app` as well; set `ignore_process_drift` on the app so that it leaves the scale and health checks of its processes to this resource, or `ignore_instances_drift` if only the instances are managed here. Changing the memory, disk or log rate limit restarts the instances of the process. Destroying the resource leaves the process as it is.",
+		Attributes: map[string]schema.Attribute{
+			idKey: guidSchema(),
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the app the process belongs to.",
+				Required:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The process type, e.g. web or worker.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"instances": schema.Int64Attribute{
+				MarkdownDescription: "The number of instances of the process.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"memory": schema.StringAttribute{
+				MarkdownDescription: "The memory limit for each instance of the process, e.g. 256M or 1G.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"disk_quota": schema.StringAttribute{
+				MarkdownDescription: "The disk limit for each instance of the process, e.g. 512M or 1G.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"log_rate_limit_per_second": schema.StringAttribute{
+				MarkdownDescription: "The log rate limit for each instance of the process, e.g. 16K, -1 for unlimited.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"health_check_type": schema.StringAttribute{
+				MarkdownDescription: "The health check type which can be one of 'port', 'process', 'http'.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("port", "process", "http"),
+				},
+			},
+			"health_check_http_endpoint": schema.StringAttribute{
+				MarkdownDescription: "The endpoint for the http health check type.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"health_check_invocation_timeout": schema.Int64Attribute{
+				MarkdownDescription: "The timeout in seconds for the health check requests for http and port health checks.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"health_check_interval": schema.Int64Attribute{
+				MarkdownDescription: "The interval in seconds between health checks.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "Time in seconds at which the health-check will report failure.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"readiness_health_check_type": schema.StringAttribute{
+				MarkdownDescription: "The readiness health check type which can be one of 'port', 'process', 'http'.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("port", "process", "http"),
+				},
+			},
+			"readiness_health_check_http_endpoint": schema.StringAttribute{
+				MarkdownDescription: "The endpoint for the http readiness health check type.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"readiness_health_check_invocation_timeout": schema.Int64Attribute{
+				MarkdownDescription: "The timeout in seconds for the readiness health check requests for http and port health checks.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"readiness_health_check_interval": schema.Int64Attribute{
+				MarkdownDescription: "The interval in seconds between readiness health checks.",
+				Optional:            true,
+				Computed:            true,
+			},
+			createdAtKey: createdAtSchema(),
+			updatedAtKey: updatedAtSchema(),
+		},
+	}
+}
+
+func (r *appProcessResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	session, ok := req.ProviderData.(*managers.Session)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *managers.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.cfClient = session.CFClient
+}
+
+func (r *appProcessResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan AppProcessType
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	process, err := getAppProcess(ctx, r.cfClient, plan.App.ValueString(), plan.Type.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Reading Process",
+			"Could not read the "+plan.Type.ValueString()+" process of app "+plan.App.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+	data, diags := r.apply(ctx, plan, process)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created an app process resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appProcessResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AppProcessType
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the process is read by its type, as deployments of the app replace the process and its GUID
+	process, err := getAppProcess(ctx, r.cfClient, data.App.ValueString(), data.Type.ValueString())
+	if err != nil {
+		handleReadErrors(ctx, resp, err, "process", data.Type.ValueString())
+		return
+	}
+
+	data, diags := data.mapProcessValuesToType(process)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Trace(ctx, "read an app process resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *appProcessResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, previousState AppProcessType
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &previousState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	process, err := getAppProcess(ctx, r.cfClient, plan.App.ValueString(), plan.Type.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Reading Process",
+			"Could not read the "+plan.Type.ValueString()+" process of app "+plan.App.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+	data, diags := r.apply(ctx, plan, process)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "updated an app process resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete only removes the process from the state, the process is deleted with its app.
+func (r *appProcessResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state AppProcessType
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "deleted an app process resource")
+}
+
+// ImportState imports the process by its GUID, the app and the type of the process are looked up to read it.
+func (r *appProcessResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	process, err := getProcess(ctx, r.cfClient, req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Reading Process",
+			"Could not read the process with ID "+req.ID+" : "+err.Error(),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), process.GUID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app"), process.appGUID())...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), process.Type)...)
+}
+
+// apply updates the health checks and scales the process to the plan, and returns the resulting values.
+func (r *appProcessResource) apply(ctx context.Context, plan AppProcessType, process *appProcess) (AppProcessType, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	scale, tempDiags := plan.mapProcessScaleTypeToValues(process)
+	diags.Append(tempDiags...)
+	if diags.HasError() {
+		return plan, diags
+	}
+	var err error
+	if update := plan.mapProcessUpdateTypeToValues(); update != nil {
+		process, err = updateProcess(ctx, r.cfClient, process.GUID, update)
+		if err != nil {
+			diags.AddError(
+				"API Error Updating Process",
+				"Could not update the health checks of process "+plan.Type.ValueString()+" : "+err.Error(),
+			)
+			return plan, diags
+		}
+	}
+	if scale != nil {
+		process, err = scaleProcess(ctx, r.cfClient, process.GUID, scale)
+		if err != nil {
+			diags.AddError(
+				"API Error Scaling Process",
+				"Could not scale process "+plan.Type.ValueString()+" : "+err.Error(),
+			)
+			return plan, diags
+		}
+	}
+	data, tempDiags := plan.mapProcessValuesToType(process)
+	diags.Append(tempDiags...)
+	return data, diags
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAppProcessResource_Configure(t *testing.T) {
+	t.Parallel()
+	resourceName := "cloudfoundry_app_process.web"
+	hclApp := `
+resource "cloudfoundry_app" "app" {
+	name                 = "tf-test-app-process"
+	space_name           = "tf-space-1"
+	org_name             = "PerformanceTeamBLR"
+	docker_image         = "kennethreitz/httpbin"
+	ignore_process_drift = true
+	no_route             = true
+}
+`
+	t.Run("happy path - scale process and update its health check", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/resource_app_process")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + hclApp + `
+resource "cloudfoundry_app_process" "web" {
+	app       = cloudfoundry_app.app.id
+	type      = "web"
+	instances = 2
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestMatchResourceAttr(resourceName, "id", regexpValidUUID),
+						resource.TestCheckResourceAttrPair(resourceName, "app", "cloudfoundry_app.app", "id"),
+						resource.TestCheckResourceAttr(resourceName, "type", "web"),
+						resource.TestCheckResourceAttr(resourceName, "instances", "2"),
+						resource.TestCheckResourceAttrSet(resourceName, "memory"),
+						resource.TestCheckResourceAttrSet(resourceName, "health_check_type"),
+					),
+				},
+				{
+					Config: hclProvider(nil) + hclApp + `
+resource "cloudfoundry_app_process" "web" {
+	app                        = cloudfoundry_app.app.id
+	type                       = "web"
+	instances                  = 1
+	health_check_type          = "http"
+	health_check_http_endpoint = "/get"
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr(resourceName, "instances", "1"),
+						resource.TestCheckResourceAttr(resourceName, "health_check_type", "http"),
+						resource.TestCheckResourceAttr(resourceName, "health_check_http_endpoint", "/get"),
+					),
+				},
+				{
+					ResourceName:      resourceName,
+					ImportStateIdFunc: getIdForImport(resourceName),
+					ImportState:       true,
+					ImportStateVerify: true,
+				},
+			},
+		})
+	})
+	t.Run("error path - manage process of unavailable app", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/resource_app_process_invalid")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+resource "cloudfoundry_app_process" "web" {
+	app       = "ec6ac2b3-fb79-43c4-9734-000d4299bd59"
+	type      = "web"
+	instances = 2
+}
+					`,
+					ExpectError: regexp.MustCompile(`API Error Reading Process`),
+				},
+			},
+		})
+	})
+}
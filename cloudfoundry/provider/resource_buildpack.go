@@ -22,6 +22,7 @@ var (
 	_ resource.ResourceWithConfigure   = &BuildpackResource{}
 	_ resource.ResourceWithImportState = &BuildpackResource{}
 	_ resource.ResourceWithIdentity    = &BuildpackResource{}
+	_ resource.ResourceWithModifyPlan  = &BuildpackResource{}
 )
 
 // Instantiates a security group resource.
@@ -91,6 +92,7 @@ func (r *BuildpackResource) Schema(ctx context.Context, req resource.SchemaReque
 					}...),
 				},
 			},
+			"source_code_digest": sourceCodeDigestSchema(),
 
 			labelsKey:      resourceLabelsSchema(),
 			annotationsKey: resourceAnnotationsSchema(),
@@ -112,6 +114,10 @@ func (rs *BuildpackResource) IdentitySchema(_ context.Context, _ resource.Identi
 	}
 }
 
+func (r *BuildpackResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanSourceCodeDigest(ctx, req, resp)
+}
+
 func (r *BuildpackResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -187,6 +193,7 @@ func (r *BuildpackResource) Create(ctx context.Context, req resource.CreateReque
 	resp.Diagnostics.Append(diags...)
 	data.Path = plan.Path
 	data.SourceCodeHash = plan.SourceCodeHash
+	data.SourceCodeDigest = sourceCodeDigestValue(ctx, plan.SourceCodeDigest, plan.Path)
 
 	tflog.Trace(ctx, "created a buildpack resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -218,6 +225,11 @@ func (rs *BuildpackResource) Read(ctx context.Context, req resource.ReadRequest,
 	resp.Diagnostics.Append(diags...)
 	state.Path = data.Path
 	state.SourceCodeHash = data.SourceCodeHash
+	state.SourceCodeDigest = data.SourceCodeDigest
+	if state.SourceCodeDigest.IsNull() {
+		// adopt the current content for states written before the digest was tracked, instead of planning an upload
+		state.SourceCodeDigest = sourceCodeDigestValue(ctx, types.StringUnknown(), state.Path)
+	}
 
 	tflog.Trace(ctx, "read a buildpack resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -255,7 +267,7 @@ func (rs *BuildpackResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
-	if !plan.Path.IsNull() && (plan.Path.ValueString() != previousState.Path.ValueString() || plan.SourceCodeHash.ValueString() != previousState.SourceCodeHash.ValueString()) {
+	if !plan.Path.IsNull() && (plan.Path.ValueString() != previousState.Path.ValueString() || plan.SourceCodeHash.ValueString() != previousState.SourceCodeHash.ValueString() || !plan.SourceCodeDigest.Equal(previousState.SourceCodeDigest)) {
 		file, err := os.Open(plan.Path.ValueString())
 		fileName := filepath.Base(plan.Path.ValueString())
 		if err != nil {
@@ -292,6 +304,7 @@ func (rs *BuildpackResource) Update(ctx context.Context, req resource.UpdateRequ
 	resp.Diagnostics.Append(diags...)
 	data.Path = plan.Path
 	data.SourceCodeHash = plan.SourceCodeHash
+	data.SourceCodeDigest = sourceCodeDigestValue(ctx, plan.SourceCodeDigest, plan.Path)
 
 	tflog.Trace(ctx, "updated a buildpack resource")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -162,7 +162,7 @@ func TestBuildpackResource_Configure(t *testing.T) {
 				{
 					ResourceName:            resourceName,
 					ImportStateIdFunc:       getIdForImport(resourceName),
-					ImportStateVerifyIgnore: []string{"path", "source_code_hash"},
+					ImportStateVerifyIgnore: []string{"path", "source_code_hash", "source_code_digest"},
 					ImportState:             true,
 					ImportStateVerify:       true,
 				},
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                = &dropletResource{}
+	_ resource.ResourceWithConfigure   = &dropletResource{}
+	_ resource.ResourceWithImportState = &dropletResource{}
+)
+
+// Instantiates a droplet resource.
+func NewDropletResource() resource.Resource {
+	return &dropletResource{}
+}
+
+// Contains reference to the v3 client to be used for making the API calls.
+type dropletResource struct {
+	cfClient *cfv3client.Client
+}
+
+func (r *dropletResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_droplet"
+}
+
+func (r *dropletResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Stages a package once into a droplet, or copies an existing droplet to another app, which may be in another space. The droplet can be deployed with the `droplet` attribute of `cloudfoundry_app`, so that every environment runs the exact same bits. Droplets are immutable, any change creates a new droplet.",
+		Attributes: map[string]schema.Attribute{
+			idKey: guidSchema(),
+			"package": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the package to stage, e.g. from `cloudfoundry_package`. The package is staged with the lifecycle of its app.",
+				Optional:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("package"), path.MatchRoot("source_droplet")),
+					stringvalidator.ConflictsWith(path.MatchRoot("app")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_droplet": schema.StringAttribute{
+				MarkdownDescription: "The GUID of a staged droplet to copy to `app`.",
+				Optional:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+					stringvalidator.AlsoRequires(path.MatchRoot("app")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the app the droplet belongs to. Required when copying `source_droplet`, otherwise the app of the package.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "The state of the droplet, 'STAGED' once it can be deployed.",
+				Computed:            true,
+			},
+			"stack": schema.StringAttribute{
+				MarkdownDescription: "The stack the droplet was staged on.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"image": schema.StringAttribute{
+				MarkdownDescription: "The docker image of the droplet of a docker package.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"checksum": schema.StringAttribute{
+				MarkdownDescription: "The checksum of the droplet bits as `<type>:<value>`, the same for a droplet and its copies.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"buildpacks": schema.ListAttribute{
+				MarkdownDescription: "The buildpacks which staged the droplet.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"process_types": schema.MapAttribute{
+				MarkdownDescription: "The start command of each process type detected during staging.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			createdAtKey: createdAtSchema(),
+			updatedAtKey: updatedAtSchema(),
+		},
+	}
+}
+
+func (r *dropletResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	session, ok := req.ProviderData.(*managers.Session)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *managers.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.cfClient = session.CFClient
+}
+
+func (r *dropletResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan DropletType
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var (
+		droplet *appDroplet
+		err     error
+	)
+	if !plan.Package.IsNull() {
+		var dropletGUID string
+		dropletGUID, err = stagePackage(ctx, r.cfClient, plan.Package.ValueString())
+		if err == nil {
+			droplet, err = getDroplet(ctx, r.cfClient, dropletGUID)
+		}
+	} else {
+		droplet, err = copyDroplet(ctx, r.cfClient, plan.SourceDroplet.ValueString(), plan.App.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Creating Droplet",
+			"Could not create droplet : "+err.Error(),
+		)
+		return
+	}
+
+	data, diags := plan.mapDropletValuesToType(ctx, droplet)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Trace(ctx, "created a droplet resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *dropletResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data DropletType
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	droplet, err := getDroplet(ctx, r.cfClient, data.Id.ValueString())
+	if err != nil {
+		handleReadErrors(ctx, resp, err, "droplet", data.Id.ValueString())
+		return
+	}
+
+	data, diags := data.mapDropletValuesToType(ctx, droplet)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Trace(ctx, "read a droplet resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only stores the plan, as every attribute which can be configured requires a new droplet.
+func (r *dropletResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, previousState DropletType
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &previousState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.State = previousState.State
+	plan.Buildpacks = previousState.Buildpacks
+	plan.ProcessTypes = previousState.ProcessTypes
+	plan.UpdatedAt = previousState.UpdatedAt
+
+	tflog.Trace(ctx, "updated a droplet resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *dropletResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state DropletType
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	jobID, err := r.cfClient.Droplets.Delete(ctx, state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Deleting Droplet",
+			"Could not delete the droplet with ID "+state.Id.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+	if err = pollJob(ctx, *r.cfClient, jobID, defaultTimeout); err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Deleting Droplet",
+			"Failed in deleting the droplet with ID "+state.Id.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a droplet resource")
+}
+
+func (r *dropletResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDropletResource_Configure(t *testing.T) {
+	t.Parallel()
+	resourceName := "cloudfoundry_droplet.droplet"
+	t.Run("happy path - stage package and copy droplet", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/resource_droplet")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+resource "cloudfoundry_package" "pkg" {
+	app  = data.cloudfoundry_app.app.id
+	path = "../../assets/cf-sample-app-nodejs.zip"
+}
+resource "cloudfoundry_droplet" "droplet" {
+	package = cloudfoundry_package.pkg.id
+}
+resource "cloudfoundry_droplet" "copy" {
+	source_droplet = cloudfoundry_droplet.droplet.id
+	app            = data.cloudfoundry_app.app.id
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestMatchResourceAttr(resourceName, "id", regexpValidUUID),
+						resource.TestCheckResourceAttrPair(resourceName, "app", "data.cloudfoundry_app.app", "id"),
+						resource.TestCheckResourceAttr(resourceName, "state", "STAGED"),
+						resource.TestCheckResourceAttrSet(resourceName, "stack"),
+						resource.TestCheckResourceAttrSet(resourceName, "checksum"),
+						resource.TestCheckResourceAttrSet(resourceName, "process_types.web"),
+						resource.TestCheckResourceAttr("cloudfoundry_droplet.copy", "state", "STAGED"),
+						resource.TestCheckResourceAttrPair("cloudfoundry_droplet.copy", "checksum", resourceName, "checksum"),
+					),
+				},
+				{
+					ResourceName:            resourceName,
+					ImportStateIdFunc:       getIdForImport(resourceName),
+					ImportStateVerifyIgnore: []string{"package"},
+					ImportState:             true,
+					ImportStateVerify:       true,
+				},
+			},
+		})
+	})
+	t.Run("error path - stage unavailable package", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/resource_droplet_invalid")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+resource "cloudfoundry_droplet" "droplet" {
+	package = "ec6ac2b3-fb79-43c4-9734-000d4299bd59"
+}
+					`,
+					ExpectError: regexp.MustCompile(`API Error Creating Droplet`),
+				},
+			},
+		})
+	})
+}
@@ -2,14 +2,20 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
 	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/mta"
 	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -26,9 +32,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// mtaFollowUpTimeout is the time given to the requests following a failed or interrupted operation, e.g. to abort it.
+const mtaFollowUpTimeout = 2 * time.Minute
+
 var (
-	_ resource.Resource              = &mtaResource{}
-	_ resource.ResourceWithConfigure = &mtaResource{}
+	_ resource.Resource                   = &mtaResource{}
+	_ resource.ResourceWithConfigure      = &mtaResource{}
+	_ resource.ResourceWithModifyPlan     = &mtaResource{}
+	_ resource.ResourceWithValidateConfig = &mtaResource{}
 )
 
 func NewMtaResource() resource.Resource {
@@ -43,7 +54,7 @@ func (r *mtaResource) Metadata(_ context.Context, req resource.MetadataRequest,
 	resp.TypeName = req.ProviderTypeName + "_mta"
 }
 
-func (r *mtaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *mtaResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: `Allows deploying applications and services via an MTAR archive or URL.
 		
@@ -51,8 +62,11 @@ __Further documentation:__
  [Multitarget Applications in the Cloud Foundry Environment](https://help.sap.com/docs/btp/sap-business-technology-platform/multitarget-applications-in-cloud-foundry-environment).
 
 __Note:__ 
- Validation of the yamls are not done from the terraform client side but via the MTA server.
- For viewing deploy logs, TF_LOG or TF_LOG_PROVIDER has to be set to INFO.
+ The deployment descriptor of a local MTA archive and the extension descriptors are validated against the MTA schema when planning, the remaining validation is done by the MTA server.
+ For viewing deploy logs, TF_LOG or TF_LOG_PROVIDER has to be set to INFO. The messages of the deploy service are logged as soon as they arrive.
+
+__Breaking change:__ 
+ Destroying an MTA no longer deletes its services. Earlier versions undeployed the MTA with deleteServices set to true, now the services, service keys and service brokers are kept unless undeploy_options deletes them. Set undeploy_options.delete_services to true to keep deleting the services on destroy.
 `,
 		Attributes: map[string]schema.Attribute{
 			"mtar_path": schema.StringAttribute{
@@ -207,6 +221,74 @@ __Note:__
 					},
 				},
 			},
+			"operation_log_lines": schema.Int64Attribute{
+				MarkdownDescription: "The number of last lines of the operation log to download from the deploy service and include in the error if the deploy or undeploy operation fails. By default the log is not downloaded.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"undeploy_options": schema.SingleNestedAttribute{
+				MarkdownDescription: "Options for undeploying the MTA when the resource is destroyed. By default the services, service keys and service brokers of the MTA are kept.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"delete_services": schema.BoolAttribute{
+						MarkdownDescription: "Delete the services of the MTA, including all data they hold. Defaults to false.",
+						Optional:            true,
+					},
+					"delete_service_keys": schema.BoolAttribute{
+						MarkdownDescription: "Delete the service keys of the MTA. Defaults to false.",
+						Optional:            true,
+					},
+					"delete_service_brokers": schema.BoolAttribute{
+						MarkdownDescription: "Delete the service brokers of the MTA. Defaults to false.",
+						Optional:            true,
+					},
+					"no_restart_subscribed_apps": schema.BoolAttribute{
+						MarkdownDescription: "Do not restart the apps subscribed to the configuration entries published by the MTA. Defaults to false.",
+						Optional:            true,
+					},
+				},
+			},
+			"on_failure": schema.StringAttribute{
+				MarkdownDescription: "How to handle a deploy or undeploy operation which fails: `abort` aborts the operation and releases its lock, `retry:N` retries it up to N times, e.g. after a transient error of a service broker, and `keep` leaves it for inspection. The error lists the actions available for a kept operation. Defaults to `keep`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^(abort|keep|retry:[1-9][0-9]*)$`), "must be abort, keep or retry:N with N the number of retries"),
+				},
+			},
+			"polling": schema.SingleNestedAttribute{
+				MarkdownDescription: "How often the deploy service is polled for the state of the upload, deploy and undeploy operations. The interval grows with every poll up to `max_interval`. By default the operations are polled every 2 seconds at first and every 10 seconds at most.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"interval": schema.Int64Attribute{
+						MarkdownDescription: "The interval in seconds between the first polls. Defaults to 2.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"max_interval": schema.Int64Attribute{
+						MarkdownDescription: "The maximum interval in seconds between two polls, at least `interval`. Defaults to 10.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+			},
+			"abort_on_timeout": schema.BoolAttribute{
+				MarkdownDescription: "Whether to abort the deploy or undeploy operation of the deploy service if the timeout is exceeded or the apply is interrupted. Otherwise the operation is left running and aborted by the next deployment of the MTA. Defaults to false.",
+				Optional:            true,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "Timeout for uploading and deploying the MTA. By default the deployment is not bounded",
+				Update:            true,
+				UpdateDescription: "Timeout for uploading and deploying the MTA. By default the deployment is not bounded",
+				Delete:            true,
+				DeleteDescription: "Timeout for undeploying the MTA. By default the undeployment is not bounded",
+			}),
 		},
 	}
 }
@@ -236,11 +318,177 @@ func (r *mtaResource) Configure(ctx context.Context, req resource.ConfigureReque
 	r.mtaClient.ChangeBasePath(deployURL)
 }
 
+// ValidateConfig validates the extension descriptors given as strings against the MTA schema.
+func (r *mtaResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var descriptorStrings types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("extension_descriptors_string"), &descriptorStrings)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, element := range descriptorStrings.Elements() {
+		content, ok := element.(types.String)
+		if !ok || content.IsNull() || content.IsUnknown() {
+			continue
+		}
+		if _, err := mta.ParseMtaDescriptor("extension descriptor", []byte(content.ValueString()), true); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("extension_descriptors_string").AtSetValue(content),
+				"Invalid MTA extension descriptor",
+				err.Error(),
+			)
+		}
+	}
+}
+
+// ModifyPlan warns about the resources which are deleted when the MTA is destroyed, when it is destroyed or the
+// destructive undeploy options are turned on, and validates the descriptors before anything is uploaded.
+func (r *mtaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var planned, previous *MtaUndeployOptionsType
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("undeploy_options"), &previous)...)
+	}
+	if !req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("undeploy_options"), &planned)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if req.Plan.Raw.IsNull() {
+		if deleted := previous.deletedResources(); len(deleted) > 0 {
+			resp.Diagnostics.AddWarning(
+				"MTA undeploy deletes resources",
+				"Destroying the MTA deletes its "+strings.Join(deleted, ", ")+" as configured in undeploy_options.",
+			)
+		}
+		return
+	}
+	if deleted := planned.deletedResources(); len(deleted) > 0 && !slices.Equal(deleted, previous.deletedResources()) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("undeploy_options"),
+			"MTA undeploy deletes resources",
+			"Once the MTA is destroyed, its "+strings.Join(deleted, ", ")+" will be deleted. Services are deleted including all their data.",
+		)
+	}
+
+	var (
+		mtarPath                                 types.String
+		extensionDescriptors, descriptorsStrings types.Set
+	)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("mtar_path"), &mtarPath)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("extension_descriptors"), &extensionDescriptors)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("extension_descriptors_string"), &descriptorsStrings)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateMtaDescriptors(mtarPath, extensionDescriptors, descriptorsStrings)...)
+}
+
+// validateMtaDescriptors validates the deployment descriptor of a local MTA archive and the extension descriptor files
+// against the MTA schema, and checks that the extension descriptors match the MTA. Files which cannot be read are
+// left to the upload to report, invalid extension descriptor strings are already reported by ValidateConfig.
+func validateMtaDescriptors(mtarPath types.String, extensionDescriptors types.Set, descriptorsStrings types.Set) diag.Diagnostics {
+	var diags diag.Diagnostics
+	type extensionDescriptor struct {
+		path       path.Path
+		descriptor mta.MtaDescriptor
+	}
+	var extensions []extensionDescriptor
+
+	for _, element := range extensionDescriptors.Elements() {
+		file, ok := element.(types.String)
+		if !ok || file.IsNull() || file.IsUnknown() {
+			continue
+		}
+		content, err := os.ReadFile(file.ValueString())
+		if err != nil {
+			continue
+		}
+		elementPath := path.Root("extension_descriptors").AtSetValue(file)
+		descriptor, err := mta.ParseMtaDescriptor(file.ValueString(), content, true)
+		if err != nil {
+			diags.AddAttributeError(elementPath, "Invalid MTA extension descriptor", err.Error())
+			continue
+		}
+		extensions = append(extensions, extensionDescriptor{elementPath, descriptor})
+	}
+	for _, element := range descriptorsStrings.Elements() {
+		content, ok := element.(types.String)
+		if !ok || content.IsNull() || content.IsUnknown() {
+			continue
+		}
+		descriptor, err := mta.ParseMtaDescriptor("extension descriptor", []byte(content.ValueString()), true)
+		if err != nil {
+			continue
+		}
+		extensions = append(extensions, extensionDescriptor{path.Root("extension_descriptors_string").AtSetValue(content), descriptor})
+	}
+
+	if mtarPath.IsNull() || mtarPath.IsUnknown() {
+		return diags
+	}
+	descriptor, err := mta.GetMtaDescriptorFromArchive(mtarPath.ValueString())
+	var descriptorErr mta.DescriptorError
+	if errors.As(err, &descriptorErr) {
+		diags.AddAttributeError(path.Root("mtar_path"), "Invalid MTA deployment descriptor", err.Error())
+		return diags
+	}
+	if err != nil {
+		return diags
+	}
+
+	descriptors := make([]mta.MtaDescriptor, 0, len(extensions))
+	for _, extension := range extensions {
+		descriptors = append(descriptors, extension.descriptor)
+	}
+	for _, extension := range extensions {
+		if err := mta.ValidateExtensionDescriptor(descriptor, extension.descriptor, descriptors); err != nil {
+			diags.AddAttributeError(extension.path, "Extension descriptor does not match the MTA", err.Error())
+		}
+	}
+	return diags
+}
+
 func (r *mtaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plannedTimeouts timeouts.Value
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeouts"), &plannedTimeouts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createTimeout, diags := plannedTimeouts.Create(ctx, 0)
+	if errors := diags.Errors(); len(errors) > 0 {
+		tflog.Warn(ctx, "reading configured create timeout", map[string]interface{}{
+			"summary": errors[0].Summary(),
+			"detail":  errors[0].Detail(),
+		})
+	}
+	ctx, cancel := contextWithOptionalTimeout(ctx, createTimeout)
+	defer cancel()
 	r.upsert(ctx, &req.Plan, nil, &resp.State, &resp.Diagnostics)
 }
 
 func (r *mtaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state MtarType
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !plan.deploymentChanged(&state) {
+		plan.Id = state.Id
+		plan.Mta = state.Mta
+		tflog.Trace(ctx, "updated an mtar resource without deploying it")
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 0)
+	if errors := diags.Errors(); len(errors) > 0 {
+		tflog.Warn(ctx, "reading configured update timeout", map[string]interface{}{
+			"summary": errors[0].Summary(),
+			"detail":  errors[0].Detail(),
+		})
+	}
+	ctx, cancel := contextWithOptionalTimeout(ctx, updateTimeout)
+	defer cancel()
 	r.upsert(ctx, &req.Plan, &req.State, &resp.State, &resp.Diagnostics)
 }
 
@@ -301,7 +549,7 @@ func (r *mtaResource) upsert(ctx context.Context, reqPlan *tfsdk.Plan, reqState
 			return
 		}
 
-		jobResponse, err := mta.PollMtaJob(ctx, r.mtaClient, spaceGuid, uploadJobID, mta.FinishedState, uploadResp.Header.Get("x-cf-app-instance"), namespace)
+		jobResponse, err := mta.PollMtaJob(ctx, r.mtaClient, spaceGuid, uploadJobID, mta.FinishedState, uploadResp.Header.Get("x-cf-app-instance"), namespace, mtarType.backoff())
 		if err != nil {
 			respDiags.AddError(
 				"Unable to poll MTAR upload job",
@@ -381,7 +629,7 @@ func (r *mtaResource) upsert(ctx context.Context, reqPlan *tfsdk.Plan, reqState
 	}
 
 	// Check for an ongoing operation for this MTA ID and abort it
-	_, err = mta.CheckOngoingOperation(ctx, r.mtaClient, mtaId, uploadedFile.Namespace, spaceGuid)
+	_, err = mta.CheckOngoingOperation(ctx, r.mtaClient, mtaId, uploadedFile.Namespace, spaceGuid, mtarType.backoff())
 	if err != nil {
 		respDiags.AddError(
 			"Unable to check for and abort ongoing MTA operation",
@@ -433,8 +681,7 @@ func (r *mtaResource) upsert(ctx context.Context, reqPlan *tfsdk.Plan, reqState
 		return
 	}
 
-	messages, err := mta.PollMtaOperation(ctx, r.mtaClient, spaceGuid, operationId, mta.FinishedState)
-	tflog.Info(ctx, messages)
+	err = r.pollOperation(ctx, mtarType, operationId)
 	if err != nil {
 		respDiags.AddError(
 			"Failure in polling MTA operation",
@@ -506,6 +753,16 @@ func (r *mtaResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
+	deleteTimeout, diags := mtarType.Timeouts.Delete(ctx, 0)
+	if errors := diags.Errors(); len(errors) > 0 {
+		tflog.Warn(ctx, "reading configured delete timeout", map[string]interface{}{
+			"summary": errors[0].Summary(),
+			"detail":  errors[0].Detail(),
+		})
+	}
+	ctx, cancel := contextWithOptionalTimeout(ctx, deleteTimeout)
+	defer cancel()
+
 	mtaId := mtarType.Id.ValueString()
 	spaceGuid := mtarType.Space.ValueString()
 
@@ -514,7 +771,7 @@ func (r *mtaResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	}
 
 	// Check for an ongoing operation for this MTA ID and abort it
-	_, err := mta.CheckOngoingOperation(ctx, r.mtaClient, mtaId, mtarType.Namespace.ValueString(), spaceGuid)
+	_, err := mta.CheckOngoingOperation(ctx, r.mtaClient, mtaId, mtarType.Namespace.ValueString(), spaceGuid, mtarType.backoff())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to check for and abort ongoing MTA operation",
@@ -526,10 +783,7 @@ func (r *mtaResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	operationParams := mta.Operation{
 		ProcessType: "UNDEPLOY",
 		Namespace:   mtarType.Namespace.ValueString(),
-		Parameters: map[string]interface{}{
-			"mtaId":          mtaId,
-			"deleteServices": true,
-		},
+		Parameters:  mapUndeployOptionsToParameters(mtaId, mtarType.UndeployOptions),
 	}
 
 	operationId, _, _, err := r.mtaClient.DefaultApi.StartMtaOperation(ctx, spaceGuid, operationParams)
@@ -541,8 +795,7 @@ func (r *mtaResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
-	messages, err := mta.PollMtaOperation(ctx, r.mtaClient, spaceGuid, operationId, mta.FinishedState)
-	tflog.Info(ctx, messages)
+	err = r.pollOperation(ctx, mtarType, operationId)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failure in polling MTA operation",
@@ -571,3 +824,108 @@ func (r *mtaResource) ImportState(ctx context.Context, req resource.ImportStateR
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("space"), spaceGuid)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), mtaId)...)
 }
+
+// pollOperation polls the MTA operation until it is finished. If the operation fails, it is retried, aborted or kept
+// for inspection as configured by on_failure. The error of a failed operation lists the actions available for it.
+func (r *mtaResource) pollOperation(ctx context.Context, mtarType MtarType, operationId string) error {
+	spaceGuid := mtarType.Space.ValueString()
+	action, retries := parseOnFailure(mtarType.OnFailure.ValueString())
+	onMessage := logMtaMessage(ctx, operationId)
+	for attempt := 1; ; attempt++ {
+		_, err := mta.PollMtaOperation(ctx, r.mtaClient, spaceGuid, operationId, mta.FinishedState, mtarType.backoff(), onMessage)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, mta.ErrOperationFailed) {
+			detail := r.operationLog(ctx, mtarType, operationId)
+			if ctx.Err() != nil && mtarType.AbortOnTimeout.ValueBool() {
+				detail += r.abortOperation(ctx, mtarType, operationId)
+			}
+			return fmt.Errorf("%w%s", err, detail)
+		}
+		if action == mtaOnFailureRetry && attempt <= retries {
+			tflog.Warn(ctx, fmt.Sprintf("Retrying failed MTA operation %s, retry %d of %d", operationId, attempt, retries), map[string]interface{}{"error": err.Error()})
+			if retryErr := mta.RetryMtaOperation(ctx, r.mtaClient, spaceGuid, operationId, mtarType.backoff()); retryErr != nil {
+				return fmt.Errorf("%w\nRetrying operation %s failed: %s", err, operationId, retryErr.Error())
+			}
+			continue
+		}
+		detail := r.operationLog(ctx, mtarType, operationId)
+		if action == mtaOnFailureAbort {
+			detail += r.abortOperation(ctx, mtarType, operationId)
+		} else {
+			detail += r.operationActions(ctx, spaceGuid, operationId)
+		}
+		return fmt.Errorf("%w%s", err, detail)
+	}
+}
+
+// abortOperation aborts the MTA operation and returns the outcome to append to the error. The abort gets its own
+// timeout, as the context of the apply may already be done.
+func (r *mtaResource) abortOperation(ctx context.Context, mtarType MtarType, operationId string) string {
+	abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mtaFollowUpTimeout)
+	defer cancel()
+	tflog.Info(ctx, "Aborting MTA operation "+operationId)
+	err := mta.AbortMtaOperation(abortCtx, r.mtaClient, mtarType.Space.ValueString(), operationId, mtarType.backoff())
+	if err != nil {
+		return "\nAborting operation " + operationId + " failed: " + err.Error()
+	}
+	return "\nOperation " + operationId + " has been aborted."
+}
+
+// operationActions returns the actions available for the failed MTA operation to append to the error, so that the
+// operation can be recovered, e.g. by retrying it with the MultiApps CLI plugin.
+func (r *mtaResource) operationActions(ctx context.Context, spaceGuid string, operationId string) string {
+	actions, _, err := r.mtaClient.DefaultApi.GetMtaOperationActions(ctx, spaceGuid, operationId)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to read the actions of MTA operation "+operationId, map[string]interface{}{"error": err.Error()})
+		return ""
+	}
+	if len(actions) == 0 {
+		return ""
+	}
+	return "\nThe operation " + operationId + " is kept, available actions: " + strings.Join(actions, ", ")
+}
+
+// operationLog downloads the last lines of the log of the failed MTA operation if configured, and returns them to
+// append to the error.
+func (r *mtaResource) operationLog(ctx context.Context, mtarType MtarType, operationId string) string {
+	if mtarType.OperationLogLines.IsNull() {
+		return ""
+	}
+	logCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mtaFollowUpTimeout)
+	defer cancel()
+	log, err := mta.GetMtaOperationLog(logCtx, r.mtaClient, mtarType.Space.ValueString(), operationId, int(mtarType.OperationLogLines.ValueInt64()))
+	if err != nil {
+		tflog.Warn(ctx, "Unable to download the log of MTA operation "+operationId, map[string]interface{}{"error": err.Error()})
+		return ""
+	}
+	if log == "" {
+		return ""
+	}
+	return "\nLast lines of the operation log:\n" + log
+}
+
+// logMtaMessage returns a callback which logs the messages of the MTA operation as they arrive, at the level of their type.
+// Messages are logged once, also when the operation is polled again after a retry.
+func logMtaMessage(ctx context.Context, operationId string) func(mta.Message) {
+	logged := map[int64]bool{}
+	return func(message mta.Message) {
+		if logged[message.Id] {
+			return
+		}
+		logged[message.Id] = true
+		fields := map[string]interface{}{
+			"operation": operationId,
+			"type":      message.Type_,
+		}
+		switch message.Type_ {
+		case "ERROR":
+			tflog.Error(ctx, message.Text, fields)
+		case "WARNING":
+			tflog.Warn(ctx, message.Text, fields)
+		default:
+			tflog.Info(ctx, message.Text, fields)
+		}
+	}
+}
@@ -250,7 +250,7 @@ EOT
 						ExtensionDescriptors: strtostrptr(`["../../assets/provider-config-local.txt"]`),
 						DeployStrategy:       strtostrptr(normalDeploy),
 					}),
-					ExpectError: regexp.MustCompile(`Failure in polling MTA operation`),
+					ExpectError: regexp.MustCompile(`Invalid MTA extension descriptor`),
 				},
 			},
 		})
@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource               = &packageResource{}
+	_ resource.ResourceWithConfigure  = &packageResource{}
+	_ resource.ResourceWithModifyPlan = &packageResource{}
+)
+
+// Instantiates a package resource.
+func NewPackageResource() resource.Resource {
+	return &packageResource{}
+}
+
+// Contains reference to the v3 client to be used for making the API calls.
+type packageResource struct {
+	cfClient *cfv3client.Client
+}
+
+func (r *packageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_package"
+}
+
+func (r *packageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Uploads the bits of an app, or references a docker image, as a package of the app which can be staged into a droplet with `cloudfoundry_droplet`. Packages are immutable, any change creates a new package.",
+		Attributes: map[string]schema.Attribute{
+			idKey: guidSchema(),
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the app the package belongs to.",
+				Required:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The path to a zip archive or a directory with the bits to upload. Files matching `.cfignore` are left out of a directory.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("docker_image"), path.MatchRoot("path")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_code_hash": schema.StringAttribute{
+				MarkdownDescription: "Used to trigger a new package. Must be set to a base64-encoded SHA256 hash of the path specified.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.Expressions{
+						path.MatchRoot("path"),
+					}...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_code_digest": schema.StringAttribute{
+				MarkdownDescription: "The SHA256 digest of the content at `path`, computed during planning. Any change of the content results in a new package, without having to set `source_code_hash`.",
+				Computed:            true,
+			},
+			"docker_image": schema.StringAttribute{
+				MarkdownDescription: "The URL to the docker image with tag e.g registry.example.com:5000/user/repository/tag or docker image name from the public repo e.g. redis:4.0",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"docker_credentials": schema.SingleNestedAttribute{
+				MarkdownDescription: "Defines login credentials for private docker repositories",
+				Optional:            true,
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRoot("docker_image")),
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"username": schema.StringAttribute{
+						MarkdownDescription: "The username for the private docker repository.",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+						Sensitive: true,
+					},
+					"password": schema.StringAttribute{
+						MarkdownDescription: "The password for the private docker repository.",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+						Sensitive: true,
+					},
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the package, either 'bits' or 'docker'.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "The state of the package, 'READY' once the bits are uploaded.",
+				Computed:            true,
+			},
+			createdAtKey: createdAtSchema(),
+			updatedAtKey: updatedAtSchema(),
+		},
+	}
+}
+
+func (r *packageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	session, ok := req.ProviderData.(*managers.Session)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *managers.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.cfClient = session.CFClient
+}
+
+// ModifyPlan plans the digest of the content at path and replaces the package if the content changed.
+func (r *packageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanSourceCodeDigest(ctx, req, resp)
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() || resp.Diagnostics.HasError() {
+		return
+	}
+	var planned, previous types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("source_code_digest"), &planned)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("source_code_digest"), &previous)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !planned.IsUnknown() && !planned.Equal(previous) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("source_code_digest"))
+	}
+}
+
+func (r *packageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan PackageType
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pkg, err := r.cfClient.Packages.Create(ctx, plan.mapCreatePackageTypeToValues())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Creating Package",
+			"Could not create package for app "+plan.App.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+	if !plan.Path.IsNull() {
+		bits, err := openAppBits(plan.Path.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid file or Path given!",
+				"Unable to open "+plan.Path.ValueString()+" : "+err.Error(),
+			)
+			return
+		}
+		defer bits.Close()
+		_, err = r.cfClient.Packages.Upload(ctx, pkg.GUID, bits)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"API Error Uploading Package",
+				"Could not upload the bits of package "+pkg.GUID+" : "+err.Error(),
+			)
+			return
+		}
+	}
+	err = r.cfClient.Packages.PollReady(ctx, pkg.GUID, stagingPollingOptions(ctx))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Uploading Package",
+			"Package "+pkg.GUID+" did not become ready : "+err.Error(),
+		)
+		return
+	}
+	pkg, err = r.cfClient.Packages.Get(ctx, pkg.GUID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Reading Package",
+			"Could not read package "+pkg.GUID+" : "+err.Error(),
+		)
+		return
+	}
+
+	plan.SourceCodeDigest = sourceCodeDigestValue(ctx, plan.SourceCodeDigest, plan.Path)
+	data := plan.mapPackageValuesToType(pkg)
+
+	tflog.Trace(ctx, "created a package resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *packageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PackageType
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pkg, err := r.cfClient.Packages.Get(ctx, data.Id.ValueString())
+	if err != nil {
+		handleReadErrors(ctx, resp, err, "package", data.Id.ValueString())
+		return
+	}
+
+	data = data.mapPackageValuesToType(pkg)
+
+	tflog.Trace(ctx, "read a package resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only stores the plan, as every attribute which can be configured requires a new package.
+func (r *packageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, previousState PackageType
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &previousState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.State = previousState.State
+	plan.UpdatedAt = previousState.UpdatedAt
+
+	tflog.Trace(ctx, "updated a package resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *packageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state PackageType
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	jobID, err := r.cfClient.Packages.Delete(ctx, state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Deleting Package",
+			"Could not delete the package with ID "+state.Id.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+	if err = pollJob(ctx, *r.cfClient, jobID, defaultTimeout); err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Deleting Package",
+			"Failed in deleting the package with ID "+state.Id.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted a package resource")
+}
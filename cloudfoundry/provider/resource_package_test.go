@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestPackageResource_Configure(t *testing.T) {
+	t.Parallel()
+	resourceName := "cloudfoundry_package.pkg"
+	t.Run("happy path - create bits package", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/resource_package_bits")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+resource "cloudfoundry_package" "pkg" {
+	app  = data.cloudfoundry_app.app.id
+	path = "../../assets/cf-sample-app-nodejs.zip"
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestMatchResourceAttr(resourceName, "id", regexpValidUUID),
+						resource.TestCheckResourceAttrPair(resourceName, "app", "data.cloudfoundry_app.app", "id"),
+						resource.TestCheckResourceAttr(resourceName, "type", "bits"),
+						resource.TestCheckResourceAttr(resourceName, "state", "READY"),
+						resource.TestCheckResourceAttrSet(resourceName, "source_code_digest"),
+						resource.TestMatchResourceAttr(resourceName, "created_at", regexpValidRFC3999Format),
+					),
+				},
+			},
+		})
+	})
+	t.Run("happy path - create docker package", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/resource_package_docker")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-http-bin"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+resource "cloudfoundry_package" "pkg" {
+	app          = data.cloudfoundry_app.app.id
+	docker_image = "kennethreitz/httpbin"
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestMatchResourceAttr(resourceName, "id", regexpValidUUID),
+						resource.TestCheckResourceAttr(resourceName, "type", "docker"),
+						resource.TestCheckResourceAttr(resourceName, "docker_image", "kennethreitz/httpbin"),
+						resource.TestCheckResourceAttr(resourceName, "state", "READY"),
+					),
+				},
+			},
+		})
+	})
+	t.Run("error path - create package with invalid path", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/resource_package_invalid")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+resource "cloudfoundry_package" "pkg" {
+	app  = data.cloudfoundry_app.app.id
+	path = "../../assets/not-existing.zip"
+}
+					`,
+					ExpectError: regexp.MustCompile(`Invalid file or Path given`),
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,266 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/cloudfoundry/provider/managers"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource              = &taskResource{}
+	_ resource.ResourceWithConfigure = &taskResource{}
+)
+
+// Instantiates a task resource.
+func NewTaskResource() resource.Resource {
+	return &taskResource{}
+}
+
+// Contains reference to the v3 client to be used for making the API calls.
+type taskResource struct {
+	cfClient *cfv3client.Client
+}
+
+func (r *taskResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_task"
+}
+
+func (r *taskResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs a one-off task on the current droplet of an app, e.g. a database migration, and waits until it succeeded. If the task fails, the apply fails with the failure reason and the last log lines of the task. The task runs once and again only if one of its attributes or `triggers` change. Destroying the resource cancels the task if it is still running.",
+		Attributes: map[string]schema.Attribute{
+			idKey: guidSchema(),
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the app to run the task on.",
+				Required:            true,
+				Validators: []validator.String{
+					validation.ValidUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"command": schema.StringAttribute{
+				MarkdownDescription: "The command to run.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the task. If not provided, a random name is generated by Cloud Foundry.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"memory": schema.StringAttribute{
+				MarkdownDescription: "The memory limit of the task, e.g. 256M or 1G. Defaults to the memory limit of the app.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk_quota": schema.StringAttribute{
+				MarkdownDescription: "The disk limit of the task, e.g. 512M or 1G. Defaults to the disk limit of the app.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"log_rate_limit_per_second": schema.StringAttribute{
+				MarkdownDescription: "The log rate limit of the task, e.g. 16K, -1 for unlimited. Defaults to the log rate limit of the app.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values, a change of which runs the task again, e.g. the version of the database schema.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"sequence_id": schema.Int64Attribute{
+				MarkdownDescription: "The user-facing id of the task, unique per app.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "The state of the task, 'SUCCEEDED' once it ran.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"failure_reason": schema.StringAttribute{
+				MarkdownDescription: "The reason why the task failed.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"droplet": schema.StringAttribute{
+				MarkdownDescription: "The GUID of the droplet the task ran with.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "Timeout for running the task, the task is cancelled once it is reached. Default is 20 minutes",
+			}),
+			createdAtKey: createdAtSchema(),
+			updatedAtKey: updatedAtSchema(),
+		},
+	}
+}
+
+func (r *taskResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	session, ok := req.ProviderData.(*managers.Session)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *managers.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.cfClient = session.CFClient
+}
+
+func (r *taskResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan TaskType
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	create, diags := plan.mapCreateTaskTypeToValues()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	task, err := createTask(ctx, r.cfClient, plan.App.ValueString(), create)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Creating Task",
+			"Could not create task on app "+plan.App.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+	task, err = waitForTask(waitCtx, r.cfClient, task.GUID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"API Error Running Task",
+			"Could not run task on app "+plan.App.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+	if task.State == taskStateFailed {
+		resp.Diagnostics.AddError(
+			"Task Failed",
+			taskFailure(ctx, r.cfClient, plan.App.ValueString(), task).Error(),
+		)
+		return
+	}
+
+	data := plan.mapTaskValuesToType(task)
+
+	tflog.Trace(ctx, "created a task resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *taskResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data TaskType
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	task, err := getTask(ctx, r.cfClient, data.Id.ValueString())
+	if err != nil {
+		handleReadErrors(ctx, resp, err, "task", data.Id.ValueString())
+		return
+	}
+
+	data = data.mapTaskValuesToType(task)
+
+	tflog.Trace(ctx, "read a task resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only stores the plan, as every attribute of the task which can be configured runs a new task.
+func (r *taskResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan TaskType
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "updated a task resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete cancels the task if it is still running, tasks which ran cannot be deleted from Cloud Foundry.
+func (r *taskResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state TaskType
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	task, err := getTask(ctx, r.cfClient, state.Id.ValueString())
+	if err != nil {
+		if cfv3resource.IsResourceNotFoundError(err) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"API Error Reading Task",
+			"Could not read the task with ID "+state.Id.ValueString()+" : "+err.Error(),
+		)
+		return
+	}
+	if task.State != taskStateSucceeded && task.State != taskStateFailed {
+		if err = cancelTask(ctx, r.cfClient, task.GUID); err != nil {
+			resp.Diagnostics.AddError(
+				"API Error Cancelling Task",
+				"Could not cancel the task with ID "+state.Id.ValueString()+" : "+err.Error(),
+			)
+			return
+		}
+	}
+
+	tflog.Trace(ctx, "deleted a task resource")
+}
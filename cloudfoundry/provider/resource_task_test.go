@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestTaskResource_Configure(t *testing.T) {
+	t.Parallel()
+	resourceName := "cloudfoundry_task.task"
+	t.Run("happy path - run task and run it again on trigger change", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/resource_task")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+resource "cloudfoundry_task" "task" {
+	app        = data.cloudfoundry_app.app.id
+	name       = "tf-test-migrate"
+	command    = "echo migrated"
+	memory     = "256M"
+	disk_quota = "512M"
+	triggers = {
+		version = "1"
+	}
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestMatchResourceAttr(resourceName, "id", regexpValidUUID),
+						resource.TestCheckResourceAttr(resourceName, "name", "tf-test-migrate"),
+						resource.TestCheckResourceAttr(resourceName, "state", "SUCCEEDED"),
+						resource.TestCheckResourceAttr(resourceName, "memory", "256M"),
+						resource.TestCheckResourceAttrSet(resourceName, "sequence_id"),
+						resource.TestMatchResourceAttr(resourceName, "droplet", regexpValidUUID),
+					),
+				},
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+resource "cloudfoundry_task" "task" {
+	app        = data.cloudfoundry_app.app.id
+	name       = "tf-test-migrate"
+	command    = "echo migrated"
+	memory     = "256M"
+	disk_quota = "512M"
+	triggers = {
+		version = "2"
+	}
+}
+					`,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr(resourceName, "state", "SUCCEEDED"),
+						resource.TestCheckResourceAttr(resourceName, "triggers.version", "2"),
+					),
+				},
+			},
+		})
+	})
+	t.Run("error path - run failing task", func(t *testing.T) {
+		cfg := getCFHomeConf()
+		rec := cfg.SetupVCR(t, "fixtures/resource_task_failed")
+		defer stopQuietly(rec)
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: getProviders(rec.GetDefaultClient()),
+			Steps: []resource.TestStep{
+				{
+					Config: hclProvider(nil) + `
+data "cloudfoundry_app" "app" {
+	name       = "tf-test-do-not-delete-nodejs"
+	space_name = "tf-space-1"
+	org_name   = "PerformanceTeamBLR"
+}
+resource "cloudfoundry_task" "task" {
+	app     = data.cloudfoundry_app.app.id
+	command = "exit 1"
+}
+					`,
+					ExpectError: regexp.MustCompile(`Task Failed`),
+				},
+			},
+		})
+	})
+}
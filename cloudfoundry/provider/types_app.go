@@ -6,14 +6,18 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	cfv3operation "github.com/cloudfoundry/go-cfclient/v3/operation"
 	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/samber/lo"
@@ -29,13 +33,28 @@ type AppType struct {
 	Buildpacks                            types.List         `tfsdk:"buildpacks"`
 	Path                                  types.String       `tfsdk:"path"`
 	SourceCodeHash                        types.String       `tfsdk:"source_code_hash"`
+	SourceCodeDigest                      types.String       `tfsdk:"source_code_digest"`
 	DockerImage                           types.String       `tfsdk:"docker_image"`
 	DockerCredentials                     *DockerCredentials `tfsdk:"docker_credentials"`
 	Strategy                              types.String       `tfsdk:"strategy"`
+	CanarySteps                           []CanaryStep       `tfsdk:"canary_steps"`
+	ResourceMatching                      types.Bool         `tfsdk:"resource_matching"`
+	Droplet                               types.String       `tfsdk:"droplet"`
+	Revision                              types.String       `tfsdk:"revision"`
+	RollbackOnFailure                     types.Bool         `tfsdk:"rollback_on_failure"`
+	Logging                               *AppLogging        `tfsdk:"logging"`
+	WaitForHealthy                        types.Bool         `tfsdk:"wait_for_healthy"`
+	IgnoreInstancesDrift                  types.Bool         `tfsdk:"ignore_instances_drift"`
+	IgnoreProcessDrift                    types.Bool         `tfsdk:"ignore_process_drift"`
+	Timeouts                              timeouts.Value     `tfsdk:"timeouts"`
 	ServiceBindings                       types.Set          `tfsdk:"service_bindings"`
+	FileBasedVcapServices                 types.Bool         `tfsdk:"file_based_vcap_services"`
+	Features                              types.Map          `tfsdk:"features"`
 	Routes                                types.Set          `tfsdk:"routes"`
 	Stopped                               types.Bool         `tfsdk:"stopped"`
 	Environment                           types.Map          `tfsdk:"environment"`
+	SensitiveEnvironment                  types.Map          `tfsdk:"sensitive_environment"`
+	EnvironmentUpdateAction               types.String       `tfsdk:"environment_update_action"`
 	HealthCheckInterval                   types.Int64        `tfsdk:"health_check_interval"`
 	ReadinessHealthCheckType              types.String       `tfsdk:"readiness_health_check_type"`
 	ReadinessHealthCheckHttpEndpoint      types.String       `tfsdk:"readiness_health_check_http_endpoint"`
@@ -68,6 +87,8 @@ type DatasourceAppType struct {
 	Space                                 types.String                 `tfsdk:"space_name"`
 	Org                                   types.String                 `tfsdk:"org_name"`
 	EnableSSH                             types.Bool                   `tfsdk:"enable_ssh"`
+	IncludeFeatures                       types.Bool                   `tfsdk:"include_features"`
+	Features                              types.Map                    `tfsdk:"features"`
 	Stack                                 types.String                 `tfsdk:"stack"`
 	Buildpacks                            types.List                   `tfsdk:"buildpacks"`
 	DockerImage                           types.String                 `tfsdk:"docker_image"`
@@ -75,6 +96,7 @@ type DatasourceAppType struct {
 	ServiceBindings                       types.Set                    `tfsdk:"service_bindings"`
 	Routes                                types.Set                    `tfsdk:"routes"`
 	Environment                           types.Map                    `tfsdk:"environment"`
+	SensitiveEnvironmentKeys              types.Set                    `tfsdk:"sensitive_environment_keys"`
 	HealthCheckInterval                   types.Int64                  `tfsdk:"health_check_interval"`
 	ReadinessHealthCheckType              types.String                 `tfsdk:"readiness_health_check_type"`
 	ReadinessHealthCheckHttpEndpoint      types.String                 `tfsdk:"readiness_health_check_http_endpoint"`
@@ -99,10 +121,12 @@ type DatasourceAppType struct {
 }
 
 type DatasourceAppsType struct {
-	Space types.String        `tfsdk:"space"`
-	Org   types.String        `tfsdk:"org"`
-	Name  types.String        `tfsdk:"name"`
-	Apps  []DatasourceAppType `tfsdk:"apps"`
+	Space                    types.String        `tfsdk:"space"`
+	Org                      types.String        `tfsdk:"org"`
+	Name                     types.String        `tfsdk:"name"`
+	SensitiveEnvironmentKeys types.Set           `tfsdk:"sensitive_environment_keys"`
+	IncludeFeatures          types.Bool          `tfsdk:"include_features"`
+	Apps                     []DatasourceAppType `tfsdk:"apps"`
 }
 
 func (a *DatasourceAppType) Expand() AppType {
@@ -135,6 +159,19 @@ type Process struct {
 	LogRateLimitPerSecond                 types.String `tfsdk:"log_rate_limit_per_second"`
 }
 
+type CanaryStep struct {
+	InstanceWeight types.Int64 `tfsdk:"instance_weight"`
+	PauseDuration  types.Int64 `tfsdk:"pause_duration"`
+	HealthCheck    types.Bool  `tfsdk:"health_check"`
+}
+
+type AppLogging struct {
+	Stream      types.Bool   `tfsdk:"stream"`
+	SourceTypes types.Set    `tfsdk:"source_types"`
+	TailLines   types.Int64  `tfsdk:"tail_lines"`
+	LogCacheURL types.String `tfsdk:"log_cache_url"`
+}
+
 type DockerCredentials struct {
 	Username types.String `tfsdk:"username"`
 	Password types.String `tfsdk:"password"`
@@ -146,12 +183,14 @@ type DataSourceDockerCredentials struct {
 
 type ServiceBinding struct {
 	ServiceInstance types.String         `tfsdk:"service_instance"`
+	Name            types.String         `tfsdk:"name"`
 	Params          jsontypes.Normalized `tfsdk:"params"`
 }
 
 var serviceBindingObjType = types.ObjectType{
 	AttrTypes: map[string]attr.Type{
 		"service_instance": types.StringType,
+		"name":             types.StringType,
 		"params":           jsontypes.NormalizedType{},
 	},
 }
@@ -203,7 +242,8 @@ func (appType *AppType) mapAppTypeToValues(ctx context.Context) (*cfv3operation.
 		diags = append(diags, tempDiags...)
 		for _, service := range tfServiceBindings {
 			serviceManifest := cfv3operation.AppManifestService{
-				Name: service.ServiceInstance.ValueString(),
+				Name:        service.ServiceInstance.ValueString(),
+				BindingName: service.Name.ValueString(),
 			}
 			if !service.Params.IsNull() {
 				var params json.RawMessage
@@ -240,10 +280,16 @@ func (appType *AppType) mapAppTypeToValues(ctx context.Context) (*cfv3operation.
 		}
 		appmanifest.Routes = &routes
 	}
-	if !appType.Environment.IsNull() {
-		var env map[string]string
-		tempDiags = appType.Environment.ElementsAs(ctx, &env, false)
-		diags = append(diags, tempDiags...)
+	if !appType.Environment.IsNull() || !appType.SensitiveEnvironment.IsNull() {
+		env := map[string]string{}
+		for _, envMap := range []types.Map{appType.Environment, appType.SensitiveEnvironment} {
+			var vars map[string]string
+			tempDiags = envMap.ElementsAs(ctx, &vars, false)
+			diags = append(diags, tempDiags...)
+			for key, value := range vars {
+				env[key] = value
+			}
+		}
 		appmanifest.Env = env
 	}
 	if !appType.HealthCheckInterval.IsNull() {
@@ -376,6 +422,7 @@ func (appType *AppType) mapAppTypeToValues(ctx context.Context) (*cfv3operation.
 		tempDiags = appType.Annotations.ElementsAs(ctx, &appmanifest.Metadata.Annotations, false)
 		diags = append(diags, tempDiags...)
 	}
+	appType.leaveProcessSettings(&appmanifest)
 	return &appmanifest, diags
 }
 
@@ -405,9 +452,23 @@ func mapAppValuesToType(ctx context.Context, appManifest *cfv3operation.AppManif
 	}
 	if appManifest.Services != nil {
 		var serviceBindings []ServiceBinding
+		tfServiceBindings := []ServiceBinding{}
+		if reqPlanType != nil && !reqPlanType.ServiceBindings.IsNull() && !reqPlanType.ServiceBindings.IsUnknown() {
+			tempDiags = reqPlanType.ServiceBindings.ElementsAs(ctx, &tfServiceBindings, false)
+			diags = append(diags, tempDiags...)
+		}
 		for _, service := range *appManifest.Services {
 			var sb ServiceBinding
 			sb.ServiceInstance = types.StringValue(service.Name)
+			planned, found := lo.Find(tfServiceBindings, func(binding ServiceBinding) bool {
+				return sb.ServiceInstance.Equal(binding.ServiceInstance)
+			})
+			sb.Name = types.StringNull()
+			if service.BindingName != "" {
+				sb.Name = types.StringValue(service.BindingName)
+			} else if found {
+				sb.Name = planned.Name
+			}
 			if service.Parameters != nil {
 				param, err := json.Marshal(service.Parameters)
 				if err != nil {
@@ -418,16 +479,8 @@ func mapAppValuesToType(ctx context.Context, appManifest *cfv3operation.AppManif
 				diags = append(diags, tempDiags...)
 			} else {
 				sb.Params = jsontypes.NewNormalizedNull()
-				if reqPlanType != nil {
-					tfServiceBindings := []ServiceBinding{}
-					tempDiags = reqPlanType.ServiceBindings.ElementsAs(ctx, &tfServiceBindings, false)
-					diags = append(diags, tempDiags...)
-					binding, found := lo.Find(tfServiceBindings, func(binding ServiceBinding) bool {
-						return sb.ServiceInstance.Equal(binding.ServiceInstance)
-					})
-					if found {
-						sb.Params = binding.Params
-					}
+				if found {
+					sb.Params = planned.Params
 				}
 			}
 			serviceBindings = append(serviceBindings, sb)
@@ -451,12 +504,12 @@ func mapAppValuesToType(ctx context.Context, appManifest *cfv3operation.AppManif
 	} else {
 		appType.Routes = types.SetNull(routeObjType)
 	}
-	if appManifest.Env != nil {
-		appType.Environment, tempDiags = types.MapValueFrom(ctx, types.StringType, appManifest.Env)
-		diags = append(diags, tempDiags...)
-	} else {
-		appType.Environment = types.MapNull(types.StringType)
+	configuredSensitiveEnv := types.MapNull(types.StringType)
+	if reqPlanType != nil {
+		configuredSensitiveEnv = reqPlanType.SensitiveEnvironment
 	}
+	appType.Environment, appType.SensitiveEnvironment, tempDiags = mapEnvironmentValuesToType(ctx, appManifest.Env, configuredSensitiveEnv)
+	diags = append(diags, tempDiags...)
 	if appManifest.Processes != nil {
 		var planProcessesByType map[string]*Process
 		if reqPlanType != nil && len(reqPlanType.Processes) > 0 {
@@ -622,7 +675,7 @@ func mapAppValuesToType(ctx context.Context, appManifest *cfv3operation.AppManif
 	}
 	if appManifest.Sidecars != nil {
 		var sidecars []Sidecar
-		for i, sidecar := range *appManifest.Sidecars {
+		for _, sidecar := range *appManifest.Sidecars {
 			var s Sidecar
 			s.Name = types.StringValue(sidecar.Name)
 			if sidecar.Command != "" {
@@ -635,8 +688,14 @@ func mapAppValuesToType(ctx context.Context, appManifest *cfv3operation.AppManif
 				s.ProcessTypes = types.SetNull(types.StringType)
 			}
 			if sidecar.Memory != "" {
-				if reqPlanType != nil && !reqPlanType.Sidecars[i].Memory.IsUnknown() {
-					result, err := getDesiredType(sidecar.Memory, reqPlanType.Sidecars[i].Memory.ValueString())
+				planned, found := Sidecar{}, false
+				if reqPlanType != nil {
+					planned, found = lo.Find(reqPlanType.Sidecars, func(s Sidecar) bool {
+						return s.Name.ValueString() == sidecar.Name
+					})
+				}
+				if found && !planned.Memory.IsNull() && !planned.Memory.IsUnknown() {
+					result, err := getDesiredType(sidecar.Memory, planned.Memory.ValueString())
 					if err != nil {
 						tempDiags.AddError("Error converting memory", err.Error())
 						diags = append(diags, tempDiags...)
@@ -667,13 +726,46 @@ func (target *AppType) CopyConfigAttributes(source *AppType) {
 	target.Org = source.Org
 	target.Path = source.Path
 	target.Strategy = source.Strategy
+	target.CanarySteps = source.CanarySteps
+	target.ResourceMatching = source.ResourceMatching
+	target.Droplet = source.Droplet
+	target.Revision = source.Revision
+	target.RollbackOnFailure = source.RollbackOnFailure
+	target.Logging = source.Logging
+	target.WaitForHealthy = source.WaitForHealthy
+	target.IgnoreInstancesDrift = source.IgnoreInstancesDrift
+	target.IgnoreProcessDrift = source.IgnoreProcessDrift
+	target.Timeouts = source.Timeouts
+	target.FileBasedVcapServices = source.FileBasedVcapServices
+	target.Features = source.Features
+	target.EnvironmentUpdateAction = source.EnvironmentUpdateAction
 	target.SourceCodeHash = source.SourceCodeHash
+	target.SourceCodeDigest = source.SourceCodeDigest
 	target.RandomRoute = source.RandomRoute
 	target.NoRoute = source.NoRoute
 	target.AppDeployedRunningTimeout = source.AppDeployedRunningTimeout
 	target.AppDeployedRunningCheckInterval = source.AppDeployedRunningCheckInterval
 }
 
+// validateCanarySteps checks that the instance weights of the canary steps are strictly increasing.
+func validateCanarySteps(steps []CanaryStep) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i := 1; i < len(steps); i++ {
+		previous, current := steps[i-1].InstanceWeight, steps[i].InstanceWeight
+		if previous.IsUnknown() || current.IsUnknown() {
+			continue
+		}
+		if current.ValueInt64() <= previous.ValueInt64() {
+			diags.AddAttributeError(
+				path.Root("canary_steps").AtListIndex(i).AtName("instance_weight"),
+				"Invalid canary step",
+				fmt.Sprintf("instance_weight of step %d must be greater than %d, the weight of the previous step", i+1, previous.ValueInt64()),
+			)
+		}
+	}
+	return diags
+}
+
 func getDesiredType(actual string, desired string) (string, error) {
 	// log-rate-limit-per-second accepts -1 & 0 as valid values
 	// For more info https://v3-apidocs.cloudfoundry.org/version/3.159.0/index.html#the-manifest-schema
@@ -734,31 +826,66 @@ func splitValueAndUnit(value string) (float64, string, error) {
 	return val, unit, nil
 }
 
-// Prepares the env for cfclient updation from existing and planned tfstate envs.
-func setEnvForUpdate(ctx context.Context, existingEnvs basetypes.MapValue, plannedEnvs basetypes.MapValue) (map[string]*string, diag.Diagnostics) {
+// quantityInUnit converts a quantity like '1G' to the whole number of the unit like 'M', the unit less
+// values '-1' and '0' are returned as is.
+func quantityInUnit(quantity string, unit string) (int, error) {
+	if quantity == "-1" || quantity == "0" {
+		return strconv.Atoi(quantity)
+	}
+	value, _, err := convertToDesiredType(quantity, "1"+unit)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q, expected a number with a unit like 256M or 1G", quantity)
+	}
+	return int(math.Floor(value)), nil
+}
+
+// quantityWithUnit formats a whole number of the unit like 'M' with the largest unit it is a multiple of, e.g. '1G' for
+// 1024 megabytes. The unit less values '-1' and '0' are returned as is.
+func quantityWithUnit(quantity int, unit string) string {
+	if quantity <= 0 {
+		return strconv.Itoa(quantity)
+	}
+	units := []string{"B", "K", "M", "G", "T"}
+	i := slices.Index(units, unit)
+	for i < len(units)-1 && quantity%1024 == 0 {
+		quantity /= 1024
+		i++
+	}
+	return strconv.Itoa(quantity) + units[i]
+}
+
+// setEnvForUpdate merges the plain and the sensitive environment and unsets the variables which are no longer planned.
+func setEnvForUpdate(ctx context.Context, existingEnvs basetypes.MapValue, existingSensitiveEnvs basetypes.MapValue, plannedEnvs basetypes.MapValue, plannedSensitiveEnvs basetypes.MapValue) (map[string]*string, diag.Diagnostics) {
 
 	var (
-		diagnostics                 diag.Diagnostics
-		oldEnvs, newEnvs, finalEnvs map[string]*string
+		diagnostics                                          diag.Diagnostics
+		oldEnvs, oldSensitiveEnvs, newEnvs, newSensitiveEnvs map[string]*string
+		finalEnvs                                            map[string]*string
 	)
 
 	finalEnvs = make(map[string]*string)
 
 	diagnostics.Append(existingEnvs.ElementsAs(ctx, &oldEnvs, false)...)
+	diagnostics.Append(existingSensitiveEnvs.ElementsAs(ctx, &oldSensitiveEnvs, false)...)
 	diagnostics.Append(plannedEnvs.ElementsAs(ctx, &newEnvs, false)...)
+	diagnostics.Append(plannedSensitiveEnvs.ElementsAs(ctx, &newSensitiveEnvs, false)...)
 
-	for key := range oldEnvs {
-		finalEnvs[key] = nil
+	for _, envs := range []map[string]*string{oldEnvs, oldSensitiveEnvs} {
+		for key := range envs {
+			finalEnvs[key] = nil
+		}
 	}
 
-	for key, value := range newEnvs {
-		finalEnvs[key] = value
+	for _, envs := range []map[string]*string{newEnvs, newSensitiveEnvs} {
+		for key, value := range envs {
+			finalEnvs[key] = value
+		}
 	}
 
 	return finalEnvs, diagnostics
 }
 
-func mapAppDatasourceValuesToType(ctx context.Context, appManifest *cfv3operation.AppManifest, app *cfv3resource.App, sshResp *cfv3resource.AppFeature) (DatasourceAppType, diag.Diagnostics) {
+func mapAppDatasourceValuesToType(ctx context.Context, appManifest *cfv3operation.AppManifest, app *cfv3resource.App, sshResp *cfv3resource.AppFeature, sensitiveEnvKeys types.Set) (DatasourceAppType, diag.Diagnostics) {
 	var diags, tempDiags diag.Diagnostics
 	var appType DatasourceAppType
 	appType.Name = types.StringValue(appManifest.Name)
@@ -781,6 +908,10 @@ func mapAppDatasourceValuesToType(ctx context.Context, appManifest *cfv3operatio
 		for _, service := range *appManifest.Services {
 			var sb ServiceBinding
 			sb.ServiceInstance = types.StringValue(service.Name)
+			sb.Name = types.StringNull()
+			if service.BindingName != "" {
+				sb.Name = types.StringValue(service.BindingName)
+			}
 			if service.Parameters != nil {
 				param, err := json.Marshal(service.Parameters)
 				if err != nil {
@@ -813,8 +944,14 @@ func mapAppDatasourceValuesToType(ctx context.Context, appManifest *cfv3operatio
 	} else {
 		appType.Routes = types.SetNull(routeObjType)
 	}
+	var redactedKeys []string
+	if !sensitiveEnvKeys.IsNull() && !sensitiveEnvKeys.IsUnknown() {
+		tempDiags = sensitiveEnvKeys.ElementsAs(ctx, &redactedKeys, false)
+		diags = append(diags, tempDiags...)
+	}
+	appType.SensitiveEnvironmentKeys = sensitiveEnvKeys
 	if appManifest.Env != nil {
-		appType.Environment, tempDiags = types.MapValueFrom(ctx, types.StringType, appManifest.Env)
+		appType.Environment, tempDiags = types.MapValueFrom(ctx, types.StringType, redactEnvironment(appManifest.Env, redactedKeys))
 		diags = append(diags, tempDiags...)
 	} else {
 		appType.Environment = types.MapNull(types.StringType)
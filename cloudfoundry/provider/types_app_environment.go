@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// appEnvironment is the response of /v3/apps/:guid/env, the effective environment of the app.
+type appEnvironment struct {
+	StagingEnvJSON       map[string]any             `json:"staging_env_json"`
+	RunningEnvJSON       map[string]any             `json:"running_env_json"`
+	EnvironmentVariables map[string]any             `json:"environment_variables"`
+	SystemEnvJSON        map[string]json.RawMessage `json:"system_env_json"`
+	ApplicationEnvJSON   map[string]json.RawMessage `json:"application_env_json"`
+}
+
+// appServiceEnvironment is an entry of VCAP_SERVICES, which are grouped by the label of the service offering.
+type appServiceEnvironment struct {
+	Name         string          `json:"name"`
+	InstanceName string          `json:"instance_name"`
+	BindingName  *string         `json:"binding_name"`
+	Plan         string          `json:"plan"`
+	Tags         []string        `json:"tags"`
+	Credentials  json.RawMessage `json:"credentials"`
+}
+
+type appEnvironmentServiceType struct {
+	Label        types.String         `tfsdk:"label"`
+	Name         types.String         `tfsdk:"name"`
+	InstanceName types.String         `tfsdk:"instance_name"`
+	BindingName  types.String         `tfsdk:"binding_name"`
+	Plan         types.String         `tfsdk:"plan"`
+	Tags         types.List           `tfsdk:"tags"`
+	Credentials  jsontypes.Normalized `tfsdk:"credentials"`
+}
+
+type appEnvironmentDatasourceType struct {
+	App                     types.String                `tfsdk:"app"`
+	Environment             types.Map                   `tfsdk:"environment"`
+	RunningEnvironmentGroup types.Map                   `tfsdk:"running_environment_group"`
+	StagingEnvironmentGroup types.Map                   `tfsdk:"staging_environment_group"`
+	VcapServices            jsontypes.Normalized        `tfsdk:"vcap_services"`
+	VcapApplication         jsontypes.Normalized        `tfsdk:"vcap_application"`
+	Services                []appEnvironmentServiceType `tfsdk:"services"`
+}
+
+func getAppEnvironment(ctx context.Context, client *cfv3client.Client, appGUID string) (*appEnvironment, error) {
+	var env appEnvironment
+	err := cfAPIRequest(ctx, client, http.MethodGet, "/v3/apps/"+appGUID+"/env", nil, &env)
+	if err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+// environmentStrings returns the environment variables as strings, values which are not strings are JSON encoded.
+func environmentStrings(env map[string]any) (map[string]string, error) {
+	values := make(map[string]string, len(env))
+	for key, value := range env {
+		if s, ok := value.(string); ok {
+			values[key] = s
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = string(raw)
+	}
+	return values, nil
+}
+
+// jsonValue returns the raw JSON as normalized value, or null if it is missing.
+func jsonValue(raw json.RawMessage) jsontypes.Normalized {
+	if len(raw) == 0 || string(raw) == "null" {
+		return jsontypes.NewNormalizedNull()
+	}
+	return jsontypes.NewNormalizedValue(string(raw))
+}
+
+func mapAppEnvironmentValuesToType(ctx context.Context, env *appEnvironment, data appEnvironmentDatasourceType) (appEnvironmentDatasourceType, diag.Diagnostics) {
+	var diags, tempDiags diag.Diagnostics
+	groups := []struct {
+		env    map[string]any
+		target *types.Map
+	}{
+		{env.EnvironmentVariables, &data.Environment},
+		{env.RunningEnvJSON, &data.RunningEnvironmentGroup},
+		{env.StagingEnvJSON, &data.StagingEnvironmentGroup},
+	}
+	for _, group := range groups {
+		values, err := environmentStrings(group.env)
+		if err != nil {
+			diags.AddError("Error encoding environment variables", err.Error())
+			return data, diags
+		}
+		*group.target, tempDiags = types.MapValueFrom(ctx, types.StringType, values)
+		diags.Append(tempDiags...)
+	}
+
+	data.VcapServices = jsonValue(env.SystemEnvJSON["VCAP_SERVICES"])
+	data.VcapApplication = jsonValue(env.ApplicationEnvJSON["VCAP_APPLICATION"])
+
+	var vcapServices map[string][]appServiceEnvironment
+	if raw := env.SystemEnvJSON["VCAP_SERVICES"]; len(raw) > 0 {
+		if err := json.Unmarshal(raw, &vcapServices); err != nil {
+			diags.AddError("Error parsing VCAP_SERVICES", err.Error())
+			return data, diags
+		}
+	}
+	data.Services = []appEnvironmentServiceType{}
+	for _, label := range slices.Sorted(maps.Keys(vcapServices)) {
+		for _, service := range vcapServices[label] {
+			s := appEnvironmentServiceType{
+				Label:        types.StringValue(label),
+				Name:         types.StringValue(service.Name),
+				InstanceName: types.StringValue(service.InstanceName),
+				BindingName:  types.StringPointerValue(service.BindingName),
+				Plan:         types.StringValue(service.Plan),
+				Credentials:  jsonValue(service.Credentials),
+			}
+			s.Tags, tempDiags = types.ListValueFrom(ctx, types.StringType, service.Tags)
+			diags.Append(tempDiags...)
+			data.Services = append(data.Services, s)
+		}
+	}
+	return data, diags
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type appLogEntryType struct {
+	Timestamp  types.String `tfsdk:"timestamp"`
+	SourceType types.String `tfsdk:"source_type"`
+	Instance   types.String `tfsdk:"instance"`
+	Type       types.String `tfsdk:"type"`
+	Stream     types.String `tfsdk:"stream"`
+	Message    types.String `tfsdk:"message"`
+}
+
+type appLogsDatasourceType struct {
+	App           types.String      `tfsdk:"app"`
+	StartTime     types.String      `tfsdk:"start_time"`
+	EndTime       types.String      `tfsdk:"end_time"`
+	Since         types.String      `tfsdk:"since"`
+	EnvelopeTypes types.Set         `tfsdk:"envelope_types"`
+	SourceTypes   types.Set         `tfsdk:"source_types"`
+	Limit         types.Int64       `tfsdk:"limit"`
+	LogCacheURL   types.String      `tfsdk:"log_cache_url"`
+	Entries       []appLogEntryType `tfsdk:"entries"`
+}
+
+// mapLogEnvelopeValuesToType maps the envelope to a log entry, the message of a metric envelope describes its values.
+func mapLogEnvelopeValuesToType(e *loggregator_v2.Envelope) appLogEntryType {
+	entry := appLogEntryType{
+		Timestamp:  types.StringValue(time.Unix(0, e.GetTimestamp()).UTC().Format(time.RFC3339Nano)),
+		SourceType: types.StringValue(e.GetTags()["source_type"]),
+		Instance:   types.StringValue(e.GetInstanceId()),
+		Stream:     types.StringNull(),
+	}
+	switch {
+	case e.GetLog() != nil:
+		entry.Type = types.StringValue("LOG")
+		entry.Stream = types.StringValue(e.GetLog().GetType().String())
+		entry.Message = types.StringValue(strings.TrimRight(string(e.GetLog().GetPayload()), "\n"))
+	case e.GetCounter() != nil:
+		counter := e.GetCounter()
+		entry.Type = types.StringValue("COUNTER")
+		entry.Message = types.StringValue(fmt.Sprintf("%s delta=%d total=%d", counter.GetName(), counter.GetDelta(), counter.GetTotal()))
+	case e.GetGauge() != nil:
+		metrics := e.GetGauge().GetMetrics()
+		values := make([]string, 0, len(metrics))
+		for _, name := range slices.Sorted(maps.Keys(metrics)) {
+			values = append(values, strings.TrimSpace(fmt.Sprintf("%s=%g %s", name, metrics[name].GetValue(), metrics[name].GetUnit())))
+		}
+		entry.Type = types.StringValue("GAUGE")
+		entry.Message = types.StringValue(strings.Join(values, " "))
+	case e.GetTimer() != nil:
+		timer := e.GetTimer()
+		entry.Type = types.StringValue("TIMER")
+		entry.Message = types.StringValue(fmt.Sprintf("%s duration=%s", timer.GetName(), time.Duration(timer.GetStop()-timer.GetStart())))
+	case e.GetEvent() != nil:
+		entry.Type = types.StringValue("EVENT")
+		entry.Message = types.StringValue(e.GetEvent().GetTitle() + ": " + e.GetEvent().GetBody())
+	default:
+		entry.Type = types.StringValue("UNKNOWN")
+		entry.Message = types.StringValue("")
+	}
+	return entry
+}
+
+// mapLogEnvelopesValuesToType maps the envelopes, which are read newest first, to log entries in chronological order.
+func mapLogEnvelopesValuesToType(envelopes []*loggregator_v2.Envelope) []appLogEntryType {
+	entries := make([]appLogEntryType, len(envelopes))
+	for i, e := range envelopes {
+		entries[len(envelopes)-1-i] = mapLogEnvelopeValuesToType(e)
+	}
+	return entries
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"testing"
+
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapLogEnvelopesValuesToType(t *testing.T) {
+	envelopes := []*loggregator_v2.Envelope{
+		{
+			Timestamp:  1714557600500000000,
+			InstanceId: "0",
+			Tags:       map[string]string{"source_type": "APP/PROC/WEB"},
+			Message: &loggregator_v2.Envelope_Log{
+				Log: &loggregator_v2.Log{Payload: []byte("Server started on port 8080\n"), Type: loggregator_v2.Log_OUT},
+			},
+		},
+		{
+			Timestamp:  1714557600000000000,
+			InstanceId: "1",
+			Tags:       map[string]string{"source_type": "APP/PROC/WEB"},
+			Message: &loggregator_v2.Envelope_Gauge{
+				Gauge: &loggregator_v2.Gauge{Metrics: map[string]*loggregator_v2.GaugeValue{
+					"memory": {Unit: "bytes", Value: 1024},
+					"cpu":    {Unit: "percentage", Value: 0.5},
+				}},
+			},
+		},
+		{
+			Timestamp: 1714557599000000000,
+			Tags:      map[string]string{"source_type": "STG"},
+			Message: &loggregator_v2.Envelope_Counter{
+				Counter: &loggregator_v2.Counter{Name: "requests", Delta: 2, Total: 10},
+			},
+		},
+	}
+	entries := mapLogEnvelopesValuesToType(envelopes)
+	assert.Len(t, entries, 3)
+
+	assert.Equal(t, "2024-05-01T09:59:59Z", entries[0].Timestamp.ValueString())
+	assert.Equal(t, "STG", entries[0].SourceType.ValueString())
+	assert.Equal(t, "COUNTER", entries[0].Type.ValueString())
+	assert.Equal(t, "requests delta=2 total=10", entries[0].Message.ValueString())
+	assert.True(t, entries[0].Stream.IsNull())
+
+	assert.Equal(t, "GAUGE", entries[1].Type.ValueString())
+	assert.Equal(t, "cpu=0.5 percentage memory=1024 bytes", entries[1].Message.ValueString())
+
+	assert.Equal(t, "2024-05-01T10:00:00.5Z", entries[2].Timestamp.ValueString())
+	assert.Equal(t, "APP/PROC/WEB", entries[2].SourceType.ValueString())
+	assert.Equal(t, "0", entries[2].Instance.ValueString())
+	assert.Equal(t, "LOG", entries[2].Type.ValueString())
+	assert.Equal(t, "OUT", entries[2].Stream.ValueString())
+	assert.Equal(t, "Server started on port 8080", entries[2].Message.ValueString())
+}
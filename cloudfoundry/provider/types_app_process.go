@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Terraform struct for storing values for the app process resource.
+type AppProcessType struct {
+	Id                                    types.String `tfsdk:"id"`
+	App                                   types.String `tfsdk:"app"`
+	Type                                  types.String `tfsdk:"type"`
+	Instances                             types.Int64  `tfsdk:"instances"`
+	Memory                                types.String `tfsdk:"memory"`
+	DiskQuota                             types.String `tfsdk:"disk_quota"`
+	LogRateLimitPerSecond                 types.String `tfsdk:"log_rate_limit_per_second"`
+	HealthCheckType                       types.String `tfsdk:"health_check_type"`
+	HealthCheckHttpEndpoint               types.String `tfsdk:"health_check_http_endpoint"`
+	HealthCheckInvocationTimeout          types.Int64  `tfsdk:"health_check_invocation_timeout"`
+	HealthCheckInterval                   types.Int64  `tfsdk:"health_check_interval"`
+	Timeout                               types.Int64  `tfsdk:"timeout"`
+	ReadinessHealthCheckType              types.String `tfsdk:"readiness_health_check_type"`
+	ReadinessHealthCheckHttpEndpoint      types.String `tfsdk:"readiness_health_check_http_endpoint"`
+	ReadinessHealthCheckInvocationTimeout types.Int64  `tfsdk:"readiness_health_check_invocation_timeout"`
+	ReadinessHealthCheckInterval          types.Int64  `tfsdk:"readiness_health_check_interval"`
+	CreatedAt                             types.String `tfsdk:"created_at"`
+	UpdatedAt                             types.String `tfsdk:"updated_at"`
+}
+
+// mapProcessScaleTypeToValues returns the scaling of the configured values which differ from the current process,
+// or nil if the process does not need to be scaled.
+func (plan *AppProcessType) mapProcessScaleTypeToValues(current *appProcess) (*appProcessScale, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var scale appProcessScale
+	changed := false
+	if !plan.Instances.IsNull() && !plan.Instances.IsUnknown() && int(plan.Instances.ValueInt64()) != current.Instances {
+		instances := int(plan.Instances.ValueInt64())
+		scale.Instances = &instances
+		changed = true
+	}
+	limits := []struct {
+		value   types.String
+		unit    string
+		path    path.Path
+		current int
+		out     **int
+	}{
+		{plan.Memory, "M", path.Root("memory"), current.MemoryInMB, &scale.MemoryInMB},
+		{plan.DiskQuota, "M", path.Root("disk_quota"), current.DiskInMB, &scale.DiskInMB},
+		{plan.LogRateLimitPerSecond, "B", path.Root("log_rate_limit_per_second"), current.LogRateLimitInBytesPerSecond, &scale.LogRateLimitInBytesPerSecond},
+	}
+	for _, limit := range limits {
+		if limit.value.IsNull() || limit.value.IsUnknown() {
+			continue
+		}
+		quantity, err := quantityInUnit(limit.value.ValueString(), limit.unit)
+		if err != nil {
+			diags.AddAttributeError(limit.path, "Invalid Process Limit", err.Error())
+			continue
+		}
+		if quantity != limit.current {
+			*limit.out = &quantity
+			changed = true
+		}
+	}
+	if !changed {
+		return nil, diags
+	}
+	return &scale, diags
+}
+
+// mapProcessUpdateTypeToValues returns the configured health checks, or nil if none are configured.
+func (plan *AppProcessType) mapProcessUpdateTypeToValues() *appProcessUpdate {
+	var update appProcessUpdate
+	healthCheck := appProcessHealthCheck{
+		Type: plan.HealthCheckType.ValueString(),
+		Data: appProcessHealthCheckData{
+			Timeout:           intPointer(plan.Timeout),
+			InvocationTimeout: intPointer(plan.HealthCheckInvocationTimeout),
+			Interval:          intPointer(plan.HealthCheckInterval),
+			Endpoint:          stringPointer(plan.HealthCheckHttpEndpoint),
+		},
+	}
+	if healthCheck.Type != "" || healthCheck.Data != (appProcessHealthCheckData{}) {
+		update.HealthCheck = &healthCheck
+	}
+	readinessHealthCheck := appProcessHealthCheck{
+		Type: plan.ReadinessHealthCheckType.ValueString(),
+		Data: appProcessHealthCheckData{
+			InvocationTimeout: intPointer(plan.ReadinessHealthCheckInvocationTimeout),
+			Interval:          intPointer(plan.ReadinessHealthCheckInterval),
+			Endpoint:          stringPointer(plan.ReadinessHealthCheckHttpEndpoint),
+		},
+	}
+	if readinessHealthCheck.Type != "" || readinessHealthCheck.Data != (appProcessHealthCheckData{}) {
+		update.ReadinessHealthCheck = &readinessHealthCheck
+	}
+	if update.HealthCheck == nil && update.ReadinessHealthCheck == nil {
+		return nil
+	}
+	return &update
+}
+
+// mapProcessValuesToType sets the values of the process, the limits are kept in the configured units if they are equal.
+func (plan AppProcessType) mapProcessValuesToType(process *appProcess) (AppProcessType, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	plan.Id = types.StringValue(process.GUID)
+	plan.App = types.StringValue(process.appGUID())
+	plan.Type = types.StringValue(process.Type)
+	plan.Instances = types.Int64Value(int64(process.Instances))
+	limits := []struct {
+		value   *types.String
+		current string
+		name    string
+	}{
+		{&plan.Memory, quantityWithUnit(process.MemoryInMB, "M"), "memory"},
+		{&plan.DiskQuota, quantityWithUnit(process.DiskInMB, "M"), "disk quota"},
+		{&plan.LogRateLimitPerSecond, quantityWithUnit(process.LogRateLimitInBytesPerSecond, "B"), "log rate limit"},
+	}
+	for _, limit := range limits {
+		if limit.value.IsNull() || limit.value.IsUnknown() {
+			*limit.value = types.StringValue(limit.current)
+			continue
+		}
+		result, err := getDesiredType(limit.current, limit.value.ValueString())
+		if err != nil {
+			diags.AddError("Error converting "+limit.name, err.Error())
+			result = limit.current
+		}
+		*limit.value = types.StringValue(result)
+	}
+	plan.HealthCheckType = types.StringValue(process.HealthCheck.Type)
+	plan.HealthCheckHttpEndpoint = types.StringPointerValue(process.HealthCheck.Data.Endpoint)
+	plan.HealthCheckInvocationTimeout = int64PointerValue(process.HealthCheck.Data.InvocationTimeout)
+	plan.HealthCheckInterval = int64PointerValue(process.HealthCheck.Data.Interval)
+	plan.Timeout = int64PointerValue(process.HealthCheck.Data.Timeout)
+	plan.ReadinessHealthCheckType = types.StringValue(process.ReadinessHealthCheck.Type)
+	plan.ReadinessHealthCheckHttpEndpoint = types.StringPointerValue(process.ReadinessHealthCheck.Data.Endpoint)
+	plan.ReadinessHealthCheckInvocationTimeout = int64PointerValue(process.ReadinessHealthCheck.Data.InvocationTimeout)
+	plan.ReadinessHealthCheckInterval = int64PointerValue(process.ReadinessHealthCheck.Data.Interval)
+	plan.CreatedAt = types.StringValue(process.CreatedAt.Format(time.RFC3339))
+	plan.UpdatedAt = types.StringValue(process.UpdatedAt.Format(time.RFC3339))
+	return plan, diags
+}
+
+// intPointer returns the value as pointer, or nil if it is null or unknown.
+func intPointer(value types.Int64) *int {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	i := int(value.ValueInt64())
+	return &i
+}
+
+// stringPointer returns the value as pointer, or nil if it is null or unknown.
+func stringPointer(value types.String) *string {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	return value.ValueStringPointer()
+}
+
+func int64PointerValue(value *int) types.Int64 {
+	if value == nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(*value))
+}
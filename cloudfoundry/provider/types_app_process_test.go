@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	cfv3operation "github.com/cloudfoundry/go-cfclient/v3/operation"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAppProcess(t *testing.T) *appProcess {
+	var process appProcess
+	err := json.Unmarshal([]byte(`{
+		"guid": "process-guid",
+		"type": "web",
+		"instances": 5,
+		"memory_in_mb": 1024,
+		"disk_in_mb": 1536,
+		"log_rate_limit_in_bytes_per_second": -1,
+		"health_check": {"type": "http", "data": {"timeout": 60, "invocation_timeout": null, "interval": null, "endpoint": "/health"}},
+		"readiness_health_check": {"type": "process", "data": {"invocation_timeout": null, "interval": null}},
+		"relationships": {"app": {"data": {"guid": "app-guid"}}},
+		"created_at": "2024-05-01T10:00:00Z",
+		"updated_at": "2024-05-01T10:05:00Z"
+	}`), &process)
+	assert.NoError(t, err)
+	return &process
+}
+
+func TestQuantityWithUnit(t *testing.T) {
+	assert.Equal(t, "1G", quantityWithUnit(1024, "M"))
+	assert.Equal(t, "1536M", quantityWithUnit(1536, "M"))
+	assert.Equal(t, "16K", quantityWithUnit(16384, "B"))
+	assert.Equal(t, "-1", quantityWithUnit(-1, "B"))
+	assert.Equal(t, "0", quantityWithUnit(0, "M"))
+}
+
+func TestMapProcessValuesToType(t *testing.T) {
+	process := testAppProcess(t)
+	plan := AppProcessType{
+		Memory:                types.StringValue("1024M"),
+		DiskQuota:             types.StringUnknown(),
+		LogRateLimitPerSecond: types.StringNull(),
+	}
+	data, diags := plan.mapProcessValuesToType(process)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "process-guid", data.Id.ValueString())
+	assert.Equal(t, "app-guid", data.App.ValueString())
+	assert.Equal(t, "web", data.Type.ValueString())
+	assert.Equal(t, int64(5), data.Instances.ValueInt64())
+	assert.Equal(t, "1024M", data.Memory.ValueString())
+	assert.Equal(t, "1536M", data.DiskQuota.ValueString())
+	assert.Equal(t, "-1", data.LogRateLimitPerSecond.ValueString())
+	assert.Equal(t, "http", data.HealthCheckType.ValueString())
+	assert.Equal(t, "/health", data.HealthCheckHttpEndpoint.ValueString())
+	assert.Equal(t, int64(60), data.Timeout.ValueInt64())
+	assert.True(t, data.HealthCheckInterval.IsNull())
+	assert.Equal(t, "process", data.ReadinessHealthCheckType.ValueString())
+	assert.True(t, data.ReadinessHealthCheckHttpEndpoint.IsNull())
+}
+
+func TestMapProcessScaleTypeToValues(t *testing.T) {
+	process := testAppProcess(t)
+	plan := AppProcessType{
+		Instances:             types.Int64Unknown(),
+		Memory:                types.StringValue("1G"),
+		DiskQuota:             types.StringNull(),
+		LogRateLimitPerSecond: types.StringNull(),
+	}
+	scale, diags := plan.mapProcessScaleTypeToValues(process)
+	assert.False(t, diags.HasError())
+	assert.Nil(t, scale)
+
+	plan.Instances = types.Int64Value(2)
+	plan.LogRateLimitPerSecond = types.StringValue("16K")
+	scale, diags = plan.mapProcessScaleTypeToValues(process)
+	assert.False(t, diags.HasError())
+	raw, err := json.Marshal(scale)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"instances": 2, "log_rate_limit_in_bytes_per_second": 16384}`, string(raw))
+}
+
+func TestMapProcessUpdateTypeToValues(t *testing.T) {
+	plan := AppProcessType{
+		HealthCheckType:                       types.StringUnknown(),
+		HealthCheckHttpEndpoint:               types.StringUnknown(),
+		HealthCheckInvocationTimeout:          types.Int64Unknown(),
+		HealthCheckInterval:                   types.Int64Null(),
+		Timeout:                               types.Int64Unknown(),
+		ReadinessHealthCheckType:              types.StringUnknown(),
+		ReadinessHealthCheckHttpEndpoint:      types.StringUnknown(),
+		ReadinessHealthCheckInvocationTimeout: types.Int64Unknown(),
+		ReadinessHealthCheckInterval:          types.Int64Unknown(),
+	}
+	assert.Nil(t, plan.mapProcessUpdateTypeToValues())
+
+	plan.HealthCheckType = types.StringValue("http")
+	plan.HealthCheckHttpEndpoint = types.StringValue("/health")
+	plan.Timeout = types.Int64Value(120)
+	raw, err := json.Marshal(plan.mapProcessUpdateTypeToValues())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"health_check": {"type": "http", "data": {"timeout": 120, "endpoint": "/health"}}}`, string(raw))
+}
+
+func TestKeepConfiguredInstances(t *testing.T) {
+	current := AppType{
+		Instances: types.Int64Value(5),
+		Processes: []Process{{Type: types.StringValue("worker"), Instances: types.Int64Value(7)}},
+	}
+	configured := AppType{
+		Instances: types.Int64Value(2),
+		Processes: []Process{{Type: types.StringValue("worker"), Instances: types.Int64Value(1)}},
+	}
+
+	data := current
+	data.Processes = append([]Process{}, current.Processes...)
+	data.keepConfiguredInstances(&configured)
+	assert.Equal(t, int64(5), data.Instances.ValueInt64())
+
+	configured.IgnoreInstancesDrift = types.BoolValue(true)
+	data.keepConfiguredInstances(&configured)
+	assert.Equal(t, int64(2), data.Instances.ValueInt64())
+	assert.Equal(t, int64(1), data.Processes[0].Instances.ValueInt64())
+}
+
+func TestLeaveProcessSettings(t *testing.T) {
+	manifest := &cfv3operation.AppManifest{
+		Processes: &cfv3operation.AppManifestProcesses{{Type: "worker", Command: "bin/worker", Memory: "1G", HealthCheckType: "process"}},
+	}
+	manifest.Memory = "512M"
+	manifest.Instances = uinttouintptr(2)
+	manifest.HealthCheckHTTPEndpoint = "/health"
+
+	appType := AppType{}
+	appType.leaveProcessSettings(manifest)
+	assert.Equal(t, "512M", manifest.Memory)
+
+	appType.IgnoreProcessDrift = types.BoolValue(true)
+	appType.leaveProcessSettings(manifest)
+	assert.Nil(t, manifest.Instances)
+	assert.Empty(t, manifest.Memory)
+	assert.Empty(t, manifest.HealthCheckHTTPEndpoint)
+	assert.Equal(t, "bin/worker", (*manifest.Processes)[0].Command)
+	assert.Empty(t, (*manifest.Processes)[0].Memory)
+	assert.Empty(t, (*manifest.Processes)[0].HealthCheckType)
+}
+
+func TestKeepUnmanagedProcessSettings(t *testing.T) {
+	current := AppType{
+		Memory:                  types.StringValue("1024M"),
+		HealthCheckHttpEndpoint: types.StringValue("/health"),
+		Processes:               []Process{{Type: types.StringValue("worker"), Timeout: types.Int64Value(60)}},
+	}
+
+	data := current
+	data.keepUnmanagedProcessSettings(&AppType{})
+	assert.Equal(t, "/health", data.HealthCheckHttpEndpoint.ValueString())
+
+	data.keepUnmanagedProcessSettings(&AppType{IgnoreProcessDrift: types.BoolValue(true)})
+	assert.Equal(t, "1024M", data.Memory.ValueString())
+	assert.True(t, data.HealthCheckHttpEndpoint.IsNull())
+	assert.True(t, data.Processes[0].Timeout.IsNull())
+}
+
+func TestInstancesChanged(t *testing.T) {
+	assert.False(t, instancesChanged(types.Int64Value(2), types.Int64Value(2)))
+	assert.False(t, instancesChanged(types.Int64Value(2), types.Int64Unknown()))
+	assert.False(t, instancesChanged(types.Int64Value(2), types.Int64Null()))
+	assert.True(t, instancesChanged(types.Int64Value(2), types.Int64Value(3)))
+	assert.True(t, instancesChanged(types.Int64Null(), types.Int64Value(3)))
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type appProcessInstanceType struct {
+	Index       types.Int64   `tfsdk:"index"`
+	State       types.String  `tfsdk:"state"`
+	Details     types.String  `tfsdk:"details"`
+	Host        types.String  `tfsdk:"host"`
+	Uptime      types.Int64   `tfsdk:"uptime"`
+	CPU         types.Float64 `tfsdk:"cpu"`
+	MemoryUsage types.Int64   `tfsdk:"memory_usage"`
+	MemoryQuota types.Int64   `tfsdk:"memory_quota"`
+	DiskUsage   types.Int64   `tfsdk:"disk_usage"`
+	DiskQuota   types.Int64   `tfsdk:"disk_quota"`
+}
+
+type appProcessType struct {
+	ID               types.String             `tfsdk:"id"`
+	Type             types.String             `tfsdk:"type"`
+	Instances        types.Int64              `tfsdk:"instances"`
+	RunningInstances types.Int64              `tfsdk:"running_instances"`
+	MemoryInMB       types.Int64              `tfsdk:"memory_in_mb"`
+	DiskInMB         types.Int64              `tfsdk:"disk_in_mb"`
+	InstanceStats    []appProcessInstanceType `tfsdk:"instance_stats"`
+}
+
+type appProcessesDatasourceType struct {
+	App       types.String     `tfsdk:"app"`
+	Type      types.String     `tfsdk:"type"`
+	Processes []appProcessType `tfsdk:"processes"`
+}
+
+// processInstanceStats is an entry of /v3/processes/:guid/stats, the quotas and usage are missing for instances which are down.
+type processInstanceStats struct {
+	Index     int64   `json:"index"`
+	State     string  `json:"state"`
+	Details   *string `json:"details"`
+	Host      string  `json:"host"`
+	Uptime    int64   `json:"uptime"`
+	MemQuota  *int64  `json:"mem_quota"`
+	DiskQuota *int64  `json:"disk_quota"`
+	Usage     struct {
+		CPU  *float64 `json:"cpu"`
+		Mem  *int64   `json:"mem"`
+		Disk *int64   `json:"disk"`
+	} `json:"usage"`
+}
+
+type processStatsList struct {
+	Resources []processInstanceStats `json:"resources"`
+}
+
+func getProcessStats(ctx context.Context, client *cfv3client.Client, processGUID string) ([]processInstanceStats, error) {
+	var stats processStatsList
+	err := cfAPIRequest(ctx, client, http.MethodGet, "/v3/processes/"+processGUID+"/stats", nil, &stats)
+	return stats.Resources, err
+}
+
+func mapProcessInstanceStatsToType(stats processInstanceStats) appProcessInstanceType {
+	instance := appProcessInstanceType{
+		Index:       types.Int64Value(stats.Index),
+		State:       types.StringValue(stats.State),
+		Details:     types.StringPointerValue(stats.Details),
+		Host:        types.StringNull(),
+		Uptime:      types.Int64Value(stats.Uptime),
+		CPU:         types.Float64PointerValue(stats.Usage.CPU),
+		MemoryUsage: types.Int64PointerValue(stats.Usage.Mem),
+		MemoryQuota: types.Int64PointerValue(stats.MemQuota),
+		DiskUsage:   types.Int64PointerValue(stats.Usage.Disk),
+		DiskQuota:   types.Int64PointerValue(stats.DiskQuota),
+	}
+	if stats.Host != "" {
+		instance.Host = types.StringValue(stats.Host)
+	}
+	return instance
+}
+
+func mapAppProcessesValuesToType(processes []*resource.Process, stats map[string][]processInstanceStats) []appProcessType {
+	processList := []appProcessType{}
+	for _, process := range processes {
+		instanceStats, ok := stats[process.GUID]
+		if !ok {
+			continue
+		}
+		sort.SliceStable(instanceStats, func(i, j int) bool {
+			return instanceStats[i].Index < instanceStats[j].Index
+		})
+		processType := appProcessType{
+			ID:            types.StringValue(process.GUID),
+			Type:          types.StringValue(process.Type),
+			Instances:     types.Int64Value(int64(process.Instances)),
+			MemoryInMB:    types.Int64Value(int64(process.MemoryInMB)),
+			DiskInMB:      types.Int64Value(int64(process.DiskInMB)),
+			InstanceStats: []appProcessInstanceType{},
+		}
+		var running int64
+		for _, s := range instanceStats {
+			if s.State == processInstanceStateRunning {
+				running++
+			}
+			processType.InstanceStats = append(processType.InstanceStats, mapProcessInstanceStatsToType(s))
+		}
+		processType.RunningInstances = types.Int64Value(running)
+		processList = append(processList, processType)
+	}
+	sort.SliceStable(processList, func(i, j int) bool {
+		return processList[i].Type.ValueString() < processList[j].Type.ValueString()
+	})
+	return processList
+}
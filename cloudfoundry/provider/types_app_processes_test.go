@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapAppProcessesValuesToType(t *testing.T) {
+	var stats processStatsList
+	err := json.Unmarshal([]byte(`{"resources": [
+		{"type": "web", "index": 1, "state": "CRASHED", "details": "Exited with status 1", "host": "", "uptime": 0,
+		 "mem_quota": null, "disk_quota": null, "usage": {}},
+		{"type": "web", "index": 0, "state": "RUNNING", "host": "10.0.0.1", "uptime": 3600,
+		 "mem_quota": 268435456, "disk_quota": 1073741824, "usage": {"cpu": 0.05, "mem": 104857600, "disk": 52428800}}
+	]}`), &stats)
+	assert.NoError(t, err)
+
+	processes := []*resource.Process{
+		{Resource: resource.Resource{GUID: "worker-guid"}, Type: "worker", Instances: 1, MemoryInMB: 512, DiskInMB: 1024},
+		{Resource: resource.Resource{GUID: "web-guid"}, Type: "web", Instances: 2, MemoryInMB: 256, DiskInMB: 1024},
+	}
+	result := mapAppProcessesValuesToType(processes, map[string][]processInstanceStats{
+		"web-guid": stats.Resources,
+	})
+
+	assert.Len(t, result, 1)
+	web := result[0]
+	assert.Equal(t, types.StringValue("web"), web.Type)
+	assert.Equal(t, types.Int64Value(2), web.Instances)
+	assert.Equal(t, types.Int64Value(1), web.RunningInstances)
+	assert.Len(t, web.InstanceStats, 2)
+
+	running := web.InstanceStats[0]
+	assert.Equal(t, types.Int64Value(0), running.Index)
+	assert.Equal(t, types.StringValue("10.0.0.1"), running.Host)
+	assert.Equal(t, types.Float64Value(0.05), running.CPU)
+	assert.Equal(t, types.Int64Value(104857600), running.MemoryUsage)
+	assert.True(t, running.Details.IsNull())
+
+	crashed := web.InstanceStats[1]
+	assert.Equal(t, types.StringValue("CRASHED"), crashed.State)
+	assert.Equal(t, types.StringValue("Exited with status 1"), crashed.Details)
+	assert.True(t, crashed.Host.IsNull())
+	assert.True(t, crashed.MemoryQuota.IsNull())
+	assert.True(t, crashed.CPU.IsNull())
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapAppRevisionsValuesToType(t *testing.T) {
+	revision := func(guid string, version int, description string) *resource.Revision {
+		return &resource.Revision{
+			Resource:    resource.Resource{GUID: guid},
+			Version:     version,
+			Description: description,
+			Deployable:  true,
+			Droplet:     resource.Relationship{GUID: "droplet-" + guid},
+			Metadata:    resource.NewMetadata(),
+		}
+	}
+	latest := revision("c", 3, "New droplet deployed.")
+
+	revisions, diags := mapAppRevisionsValuesToType(context.Background(),
+		[]*resource.Revision{latest, revision("a", 1, ""), revision("b", 2, "Rolled back to revision 1.")},
+		[]*resource.Revision{latest},
+	)
+
+	assert.False(t, diags.HasError())
+	assert.Len(t, revisions, 3)
+	for i, r := range revisions {
+		assert.Equal(t, int64(i+1), r.Version.ValueInt64(), "revisions should be ordered by version")
+		assert.Equal(t, i == 2, r.Deployed.ValueBool())
+	}
+	assert.True(t, revisions[0].Description.IsNull())
+	assert.Equal(t, "droplet-c", revisions[2].Droplet.ValueString())
+}
@@ -10,20 +10,21 @@ import (
 )
 
 type buildpackType struct {
-	Name           types.String `tfsdk:"name"`
-	Id             types.String `tfsdk:"id"`
-	Path           types.String `tfsdk:"path"`
-	State          types.String `tfsdk:"state"`
-	Stack          types.String `tfsdk:"stack"`
-	Filename       types.String `tfsdk:"filename"`
-	Position       types.Int64  `tfsdk:"position"`
-	Enabled        types.Bool   `tfsdk:"enabled"`
-	Locked         types.Bool   `tfsdk:"locked"`
-	Labels         types.Map    `tfsdk:"labels"`
-	Annotations    types.Map    `tfsdk:"annotations"`
-	CreatedAt      types.String `tfsdk:"created_at"`
-	UpdatedAt      types.String `tfsdk:"updated_at"`
-	SourceCodeHash types.String `tfsdk:"source_code_hash"`
+	Name             types.String `tfsdk:"name"`
+	Id               types.String `tfsdk:"id"`
+	Path             types.String `tfsdk:"path"`
+	State            types.String `tfsdk:"state"`
+	Stack            types.String `tfsdk:"stack"`
+	Filename         types.String `tfsdk:"filename"`
+	Position         types.Int64  `tfsdk:"position"`
+	Enabled          types.Bool   `tfsdk:"enabled"`
+	Locked           types.Bool   `tfsdk:"locked"`
+	Labels           types.Map    `tfsdk:"labels"`
+	Annotations      types.Map    `tfsdk:"annotations"`
+	CreatedAt        types.String `tfsdk:"created_at"`
+	UpdatedAt        types.String `tfsdk:"updated_at"`
+	SourceCodeHash   types.String `tfsdk:"source_code_hash"`
+	SourceCodeDigest types.String `tfsdk:"source_code_digest"`
 }
 
 type datasourceBuildpackType struct {
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Terraform struct for storing values for the droplet resource.
+type DropletType struct {
+	Id            types.String `tfsdk:"id"`
+	Package       types.String `tfsdk:"package"`
+	SourceDroplet types.String `tfsdk:"source_droplet"`
+	App           types.String `tfsdk:"app"`
+	State         types.String `tfsdk:"state"`
+	Stack         types.String `tfsdk:"stack"`
+	Image         types.String `tfsdk:"image"`
+	Checksum      types.String `tfsdk:"checksum"`
+	Buildpacks    types.List   `tfsdk:"buildpacks"`
+	ProcessTypes  types.Map    `tfsdk:"process_types"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+	UpdatedAt     types.String `tfsdk:"updated_at"`
+}
+
+// mapDropletValuesToType sets the values of the droplet, the configured package and source droplet are kept.
+func (plan DropletType) mapDropletValuesToType(ctx context.Context, droplet *appDroplet) (DropletType, diag.Diagnostics) {
+	var diags, tempDiags diag.Diagnostics
+	plan.Id = types.StringValue(droplet.GUID)
+	plan.App = types.StringValue(droplet.appGUID())
+	plan.State = types.StringValue(droplet.State)
+	plan.Stack = types.StringPointerValue(droplet.Stack)
+	plan.Image = types.StringPointerValue(droplet.Image)
+	plan.Checksum = types.StringNull()
+	if droplet.Checksum != nil {
+		plan.Checksum = types.StringValue(droplet.Checksum.Type + ":" + droplet.Checksum.Value)
+	}
+	buildpacks := make([]string, 0, len(droplet.Buildpacks))
+	for _, buildpack := range droplet.Buildpacks {
+		name := buildpack.BuildpackName
+		if name == "" {
+			name = buildpack.Name
+		}
+		buildpacks = append(buildpacks, name)
+	}
+	plan.Buildpacks, tempDiags = types.ListValueFrom(ctx, types.StringType, buildpacks)
+	diags.Append(tempDiags...)
+	processTypes := droplet.ProcessTypes
+	if processTypes == nil {
+		processTypes = map[string]string{}
+	}
+	plan.ProcessTypes, tempDiags = types.MapValueFrom(ctx, types.StringType, processTypes)
+	diags.Append(tempDiags...)
+	plan.CreatedAt = types.StringValue(droplet.CreatedAt.Format(time.RFC3339))
+	plan.UpdatedAt = types.StringValue(droplet.UpdatedAt.Format(time.RFC3339))
+	return plan, diags
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapDropletValuesToType(t *testing.T) {
+	var droplet appDroplet
+	err := json.Unmarshal([]byte(`{
+		"guid": "droplet-guid",
+		"state": "STAGED",
+		"error": null,
+		"stack": "cflinuxfs4",
+		"image": null,
+		"process_types": {"web": "bundle exec rackup"},
+		"checksum": {"type": "sha256", "value": "abc123"},
+		"buildpacks": [{"name": "ruby_buildpack", "buildpack_name": "ruby", "version": "1.8.0"}, {"name": "binary_buildpack"}],
+		"relationships": {"app": {"data": {"guid": "app-guid"}}},
+		"created_at": "2024-05-01T10:00:00Z",
+		"updated_at": "2024-05-01T10:05:00Z"
+	}`), &droplet)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	plan := DropletType{Package: types.StringValue("package-guid"), SourceDroplet: types.StringNull()}
+	data, diags := plan.mapDropletValuesToType(ctx, &droplet)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "droplet-guid", data.Id.ValueString())
+	assert.Equal(t, "package-guid", data.Package.ValueString())
+	assert.True(t, data.SourceDroplet.IsNull())
+	assert.Equal(t, "app-guid", data.App.ValueString())
+	assert.Equal(t, "cflinuxfs4", data.Stack.ValueString())
+	assert.True(t, data.Image.IsNull())
+	assert.Equal(t, "sha256:abc123", data.Checksum.ValueString())
+	assert.Equal(t, "2024-05-01T10:05:00Z", data.UpdatedAt.ValueString())
+
+	var buildpacks []string
+	data.Buildpacks.ElementsAs(ctx, &buildpacks, false)
+	assert.Equal(t, []string{"ruby", "binary_buildpack"}, buildpacks)
+	var processTypes map[string]string
+	data.ProcessTypes.ElementsAs(ctx, &processTypes, false)
+	assert.Equal(t, map[string]string{"web": "bundle exec rackup"}, processTypes)
+}
+
+func TestDropletAppGUID(t *testing.T) {
+	assert.Empty(t, (&appDroplet{}).appGUID())
+}
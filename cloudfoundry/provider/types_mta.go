@@ -2,28 +2,56 @@ package provider
 
 import (
 	"context"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/mta"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+const (
+	mtaOnFailureAbort = "abort"
+	mtaOnFailureRetry = "retry"
+	mtaOnFailureKeep  = "keep"
+)
+
 type MtarType struct {
-	MtarPath                   types.String `tfsdk:"mtar_path"`
-	MtarUrl                    types.String `tfsdk:"mtar_url"`
-	ExtensionDescriptors       types.Set    `tfsdk:"extension_descriptors"`
-	ExtensionDescriptorsString types.Set    `tfsdk:"extension_descriptors_string"`
-	DeployUrl                  types.String `tfsdk:"deploy_url"`
-	Space                      types.String `tfsdk:"space"`
-	Mta                        types.Object `tfsdk:"mta"`
-	Namespace                  types.String `tfsdk:"namespace"`
-	Id                         types.String `tfsdk:"id"`
-	SourceCodeHash             types.String `tfsdk:"source_code_hash"`
-	DeployStrategy             types.String `tfsdk:"deploy_strategy"`
-	SkipIdleStart              types.Bool   `tfsdk:"skip_idle_start"`
-	VersionRule                types.String `tfsdk:"version_rule"`
-	Modules                    types.Set    `tfsdk:"modules"`
+	MtarPath                   types.String            `tfsdk:"mtar_path"`
+	MtarUrl                    types.String            `tfsdk:"mtar_url"`
+	ExtensionDescriptors       types.Set               `tfsdk:"extension_descriptors"`
+	ExtensionDescriptorsString types.Set               `tfsdk:"extension_descriptors_string"`
+	DeployUrl                  types.String            `tfsdk:"deploy_url"`
+	Space                      types.String            `tfsdk:"space"`
+	Mta                        types.Object            `tfsdk:"mta"`
+	Namespace                  types.String            `tfsdk:"namespace"`
+	Id                         types.String            `tfsdk:"id"`
+	SourceCodeHash             types.String            `tfsdk:"source_code_hash"`
+	DeployStrategy             types.String            `tfsdk:"deploy_strategy"`
+	SkipIdleStart              types.Bool              `tfsdk:"skip_idle_start"`
+	VersionRule                types.String            `tfsdk:"version_rule"`
+	Modules                    types.Set               `tfsdk:"modules"`
+	AbortOnTimeout             types.Bool              `tfsdk:"abort_on_timeout"`
+	OperationLogLines          types.Int64             `tfsdk:"operation_log_lines"`
+	OnFailure                  types.String            `tfsdk:"on_failure"`
+	UndeployOptions            *MtaUndeployOptionsType `tfsdk:"undeploy_options"`
+	Polling                    *MtaPollingType         `tfsdk:"polling"`
+	Timeouts                   timeouts.Value          `tfsdk:"timeouts"`
+}
+
+type MtaUndeployOptionsType struct {
+	DeleteServices          types.Bool `tfsdk:"delete_services"`
+	DeleteServiceKeys       types.Bool `tfsdk:"delete_service_keys"`
+	DeleteServiceBrokers    types.Bool `tfsdk:"delete_service_brokers"`
+	NoRestartSubscribedApps types.Bool `tfsdk:"no_restart_subscribed_apps"`
+}
+
+type MtaPollingType struct {
+	Interval    types.Int64 `tfsdk:"interval"`
+	MaxInterval types.Int64 `tfsdk:"max_interval"`
 }
 
 type MtasDataSourceType struct {
@@ -182,3 +210,100 @@ func mapMtaModuleValuesToType(ctx context.Context, module mta.Module) (MtaModule
 	diagnostics.Append(diags...)
 	return mtaModuleType, diags
 }
+
+// deploymentChanged reports whether the planned attributes differ in any attribute which is deployed, changes of the
+// attributes which only configure the provider, e.g. the timeouts, do not require a new deployment.
+func (plan *MtarType) deploymentChanged(state *MtarType) bool {
+	pairs := [][2]attr.Value{
+		{state.MtarPath, plan.MtarPath},
+		{state.MtarUrl, plan.MtarUrl},
+		{state.ExtensionDescriptors, plan.ExtensionDescriptors},
+		{state.ExtensionDescriptorsString, plan.ExtensionDescriptorsString},
+		{state.DeployUrl, plan.DeployUrl},
+		{state.Space, plan.Space},
+		{state.Namespace, plan.Namespace},
+		{state.SourceCodeHash, plan.SourceCodeHash},
+		{state.DeployStrategy, plan.DeployStrategy},
+		{state.SkipIdleStart, plan.SkipIdleStart},
+		{state.VersionRule, plan.VersionRule},
+		{state.Modules, plan.Modules},
+	}
+	for _, pair := range pairs {
+		if !pair[1].Equal(pair[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOnFailure returns the action to take if an MTA operation fails and the number of retries for the retry action.
+// A failed operation is kept if no action is configured.
+func parseOnFailure(value string) (string, int) {
+	if value == "" {
+		return mtaOnFailureKeep, 0
+	}
+	action, count, found := strings.Cut(value, ":")
+	if !found {
+		return action, 0
+	}
+	retries, err := strconv.Atoi(count)
+	if err != nil {
+		return action, 0
+	}
+	return action, retries
+}
+
+// deletedResources returns the kinds of resources which are deleted by undeploying the MTA with the options.
+func (options *MtaUndeployOptionsType) deletedResources() []string {
+	var deleted []string
+	if options == nil {
+		return deleted
+	}
+	if options.DeleteServices.ValueBool() {
+		deleted = append(deleted, "services")
+	}
+	if options.DeleteServiceKeys.ValueBool() {
+		deleted = append(deleted, "service keys")
+	}
+	if options.DeleteServiceBrokers.ValueBool() {
+		deleted = append(deleted, "service brokers")
+	}
+	return deleted
+}
+
+// backoff returns the backoff to poll the operations of the MTA with, the default one unless polling is configured.
+func (mtarType *MtarType) backoff() mta.Backoff {
+	backoff := mta.DefaultBackoff
+	if mtarType.Polling == nil {
+		return backoff
+	}
+	if !mtarType.Polling.Interval.IsNull() {
+		backoff.Interval = time.Duration(mtarType.Polling.Interval.ValueInt64()) * time.Second
+	}
+	if !mtarType.Polling.MaxInterval.IsNull() {
+		backoff.MaxInterval = time.Duration(mtarType.Polling.MaxInterval.ValueInt64()) * time.Second
+	}
+	if backoff.MaxInterval < backoff.Interval {
+		backoff.MaxInterval = backoff.Interval
+	}
+	return backoff
+}
+
+// mapUndeployOptionsToParameters returns the parameters of the UNDEPLOY operation, which keeps all services, service
+// keys and service brokers unless configured otherwise.
+func mapUndeployOptionsToParameters(mtaId string, options *MtaUndeployOptionsType) map[string]interface{} {
+	parameters := map[string]interface{}{
+		"mtaId":                   mtaId,
+		"deleteServices":          false,
+		"deleteServiceKeys":       false,
+		"deleteServiceBrokers":    false,
+		"noRestartSubscribedApps": false,
+	}
+	if options != nil {
+		parameters["deleteServices"] = options.DeleteServices.ValueBool()
+		parameters["deleteServiceKeys"] = options.DeleteServiceKeys.ValueBool()
+		parameters["deleteServiceBrokers"] = options.DeleteServiceBrokers.ValueBool()
+		parameters["noRestartSubscribedApps"] = options.NoRestartSubscribedApps.ValueBool()
+	}
+	return parameters
+}
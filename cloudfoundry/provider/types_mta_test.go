@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/mta"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMtaDeploymentChanged(t *testing.T) {
+	state := MtarType{
+		MtarPath:                   types.StringValue("my-mta_1.0.0.mtar"),
+		ExtensionDescriptors:       types.SetNull(types.StringType),
+		ExtensionDescriptorsString: types.SetNull(types.StringType),
+		Space:                      types.StringValue("02c0cc92-6ecc-44b1-b7b2-096ca19ee143"),
+		SkipIdleStart:              types.BoolValue(true),
+		Modules:                    types.SetNull(types.StringType),
+		AbortOnTimeout:             types.BoolNull(),
+	}
+	plan := state
+	plan.AbortOnTimeout = types.BoolValue(true)
+	plan.Id = types.StringUnknown()
+	assert.False(t, plan.deploymentChanged(&state))
+
+	plan.SourceCodeHash = types.StringValue("fca8f8d1")
+	assert.True(t, plan.deploymentChanged(&state))
+}
+
+func TestParseOnFailure(t *testing.T) {
+	action, retries := parseOnFailure("")
+	assert.Equal(t, mtaOnFailureKeep, action)
+	assert.Equal(t, 0, retries)
+
+	action, retries = parseOnFailure("abort")
+	assert.Equal(t, mtaOnFailureAbort, action)
+	assert.Equal(t, 0, retries)
+
+	action, retries = parseOnFailure("retry:3")
+	assert.Equal(t, mtaOnFailureRetry, action)
+	assert.Equal(t, 3, retries)
+}
+
+func TestMapUndeployOptionsToParameters(t *testing.T) {
+	parameters := mapUndeployOptionsToParameters("my-mta", nil)
+	assert.Equal(t, "my-mta", parameters["mtaId"])
+	assert.Equal(t, false, parameters["deleteServices"])
+	assert.Equal(t, false, parameters["deleteServiceKeys"])
+
+	options := &MtaUndeployOptionsType{
+		DeleteServices:          types.BoolValue(true),
+		DeleteServiceKeys:       types.BoolNull(),
+		DeleteServiceBrokers:    types.BoolValue(false),
+		NoRestartSubscribedApps: types.BoolValue(true),
+	}
+	parameters = mapUndeployOptionsToParameters("my-mta", options)
+	assert.Equal(t, true, parameters["deleteServices"])
+	assert.Equal(t, false, parameters["deleteServiceKeys"])
+	assert.Equal(t, false, parameters["deleteServiceBrokers"])
+	assert.Equal(t, true, parameters["noRestartSubscribedApps"])
+	assert.Equal(t, []string{"services"}, options.deletedResources())
+
+	var none *MtaUndeployOptionsType
+	assert.Empty(t, none.deletedResources())
+}
+
+func TestMtaBackoff(t *testing.T) {
+	var mtarType MtarType
+	assert.Equal(t, mta.DefaultBackoff, mtarType.backoff())
+
+	mtarType.Polling = &MtaPollingType{Interval: types.Int64Value(5), MaxInterval: types.Int64Null()}
+	backoff := mtarType.backoff()
+	assert.Equal(t, 5*time.Second, backoff.Interval)
+	assert.Equal(t, 10*time.Second, backoff.MaxInterval)
+	assert.Equal(t, mta.DefaultBackoff.Factor, backoff.Factor)
+
+	mtarType.Polling.Interval = types.Int64Value(30)
+	assert.Equal(t, 30*time.Second, mtarType.backoff().MaxInterval)
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"time"
+
+	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Terraform struct for storing values for the package resource.
+type PackageType struct {
+	Id                types.String       `tfsdk:"id"`
+	App               types.String       `tfsdk:"app"`
+	Path              types.String       `tfsdk:"path"`
+	SourceCodeHash    types.String       `tfsdk:"source_code_hash"`
+	SourceCodeDigest  types.String       `tfsdk:"source_code_digest"`
+	DockerImage       types.String       `tfsdk:"docker_image"`
+	DockerCredentials *DockerCredentials `tfsdk:"docker_credentials"`
+	Type              types.String       `tfsdk:"type"`
+	State             types.String       `tfsdk:"state"`
+	CreatedAt         types.String       `tfsdk:"created_at"`
+	UpdatedAt         types.String       `tfsdk:"updated_at"`
+}
+
+// mapCreatePackageTypeToValues returns the package to create for the bits at path or the docker image.
+func (plan *PackageType) mapCreatePackageTypeToValues() *cfv3resource.PackageCreate {
+	if plan.DockerImage.IsNull() {
+		return cfv3resource.NewPackageCreate(plan.App.ValueString())
+	}
+	var username, password string
+	if plan.DockerCredentials != nil {
+		username = plan.DockerCredentials.Username.ValueString()
+		password = plan.DockerCredentials.Password.ValueString()
+	}
+	return cfv3resource.NewDockerPackageCreate(plan.App.ValueString(), plan.DockerImage.ValueString(), username, password)
+}
+
+// mapPackageValuesToType sets the computed values of the package, the configuration is kept from the plan or state.
+func (plan PackageType) mapPackageValuesToType(pkg *cfv3resource.Package) PackageType {
+	plan.Id = types.StringValue(pkg.GUID)
+	plan.Type = types.StringValue(pkg.Type)
+	plan.State = types.StringValue(string(pkg.State))
+	plan.CreatedAt = types.StringValue(pkg.CreatedAt.Format(time.RFC3339))
+	plan.UpdatedAt = types.StringValue(pkg.UpdatedAt.Format(time.RFC3339))
+	return plan
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Terraform struct for storing values for the task resource.
+type TaskType struct {
+	Id                    types.String   `tfsdk:"id"`
+	App                   types.String   `tfsdk:"app"`
+	Command               types.String   `tfsdk:"command"`
+	Name                  types.String   `tfsdk:"name"`
+	Memory                types.String   `tfsdk:"memory"`
+	DiskQuota             types.String   `tfsdk:"disk_quota"`
+	LogRateLimitPerSecond types.String   `tfsdk:"log_rate_limit_per_second"`
+	Triggers              types.Map      `tfsdk:"triggers"`
+	SequenceId            types.Int64    `tfsdk:"sequence_id"`
+	State                 types.String   `tfsdk:"state"`
+	FailureReason         types.String   `tfsdk:"failure_reason"`
+	Droplet               types.String   `tfsdk:"droplet"`
+	CreatedAt             types.String   `tfsdk:"created_at"`
+	UpdatedAt             types.String   `tfsdk:"updated_at"`
+	Timeouts              timeouts.Value `tfsdk:"timeouts"`
+}
+
+type taskDatasourceType struct {
+	Id                    types.String `tfsdk:"id"`
+	SequenceId            types.Int64  `tfsdk:"sequence_id"`
+	Name                  types.String `tfsdk:"name"`
+	Command               types.String `tfsdk:"command"`
+	State                 types.String `tfsdk:"state"`
+	MemoryInMB            types.Int64  `tfsdk:"memory_in_mb"`
+	DiskInMB              types.Int64  `tfsdk:"disk_in_mb"`
+	LogRateLimitPerSecond types.Int64  `tfsdk:"log_rate_limit_per_second"`
+	FailureReason         types.String `tfsdk:"failure_reason"`
+	Droplet               types.String `tfsdk:"droplet"`
+	CreatedAt             types.String `tfsdk:"created_at"`
+	UpdatedAt             types.String `tfsdk:"updated_at"`
+}
+
+type tasksDatasourceType struct {
+	App    types.String         `tfsdk:"app"`
+	Name   types.String         `tfsdk:"name"`
+	States types.Set            `tfsdk:"states"`
+	Tasks  []taskDatasourceType `tfsdk:"tasks"`
+}
+
+// mapCreateTaskTypeToValues returns the task to create, the limits are converted to the units of the CF API.
+func (plan *TaskType) mapCreateTaskTypeToValues() (appTaskCreate, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	create := appTaskCreate{
+		Command: plan.Command.ValueString(),
+		Name:    plan.Name.ValueString(),
+	}
+	limits := []struct {
+		value types.String
+		unit  string
+		path  path.Path
+		out   **int
+	}{
+		{plan.Memory, "M", path.Root("memory"), &create.MemoryInMB},
+		{plan.DiskQuota, "M", path.Root("disk_quota"), &create.DiskInMB},
+		{plan.LogRateLimitPerSecond, "B", path.Root("log_rate_limit_per_second"), &create.LogRateLimitInBytesPerSecond},
+	}
+	for _, limit := range limits {
+		if limit.value.IsNull() || limit.value.IsUnknown() {
+			continue
+		}
+		quantity, err := quantityInUnit(limit.value.ValueString(), limit.unit)
+		if err != nil {
+			diags.AddAttributeError(limit.path, "Invalid Task Limit", err.Error())
+			continue
+		}
+		*limit.out = &quantity
+	}
+	return create, diags
+}
+
+// mapTaskValuesToType sets the computed values of the task, the configured app, command, limits and triggers are kept.
+func (plan TaskType) mapTaskValuesToType(task *appTask) TaskType {
+	plan.Id = types.StringValue(task.GUID)
+	plan.Name = types.StringValue(task.Name)
+	plan.SequenceId = types.Int64Value(int64(task.SequenceID))
+	plan.State = types.StringValue(task.State)
+	plan.FailureReason = types.StringPointerValue(task.Result.FailureReason)
+	plan.Droplet = types.StringValue(task.DropletGUID)
+	plan.CreatedAt = types.StringValue(task.CreatedAt.Format(time.RFC3339))
+	plan.UpdatedAt = types.StringValue(task.UpdatedAt.Format(time.RFC3339))
+	return plan
+}
+
+func mapTaskDatasourceValuesToType(task *appTask) taskDatasourceType {
+	return taskDatasourceType{
+		Id:                    types.StringValue(task.GUID),
+		SequenceId:            types.Int64Value(int64(task.SequenceID)),
+		Name:                  types.StringValue(task.Name),
+		Command:               types.StringValue(task.Command),
+		State:                 types.StringValue(task.State),
+		MemoryInMB:            types.Int64Value(int64(task.MemoryInMB)),
+		DiskInMB:              types.Int64Value(int64(task.DiskInMB)),
+		LogRateLimitPerSecond: types.Int64Value(int64(task.LogRateLimitInBytesPerSecond)),
+		FailureReason:         types.StringPointerValue(task.Result.FailureReason),
+		Droplet:               types.StringValue(task.DropletGUID),
+		CreatedAt:             types.StringValue(task.CreatedAt.Format(time.RFC3339)),
+		UpdatedAt:             types.StringValue(task.UpdatedAt.Format(time.RFC3339)),
+	}
+}
+
+func mapTasksDatasourceValuesToType(tasks []*appTask) []taskDatasourceType {
+	tasksList := []taskDatasourceType{}
+	for _, task := range tasks {
+		tasksList = append(tasksList, mapTaskDatasourceValuesToType(task))
+	}
+	return tasksList
+}
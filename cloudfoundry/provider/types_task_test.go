@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantityInUnit(t *testing.T) {
+	tests := []struct {
+		quantity string
+		unit     string
+		expected int
+	}{
+		{"256M", "M", 256},
+		{"1G", "M", 1024},
+		{"1.5g", "M", 1536},
+		{"16K", "B", 16384},
+		{"-1", "B", -1},
+		{"0", "B", 0},
+	}
+	for _, test := range tests {
+		quantity, err := quantityInUnit(test.quantity, test.unit)
+		assert.NoError(t, err, test.quantity)
+		assert.Equal(t, test.expected, quantity, test.quantity)
+	}
+	_, err := quantityInUnit("256", "M")
+	assert.Error(t, err)
+}
+
+func TestMapCreateTaskTypeToValues(t *testing.T) {
+	plan := TaskType{
+		Command:               types.StringValue("bin/rails db:migrate"),
+		Name:                  types.StringUnknown(),
+		Memory:                types.StringValue("1G"),
+		DiskQuota:             types.StringNull(),
+		LogRateLimitPerSecond: types.StringValue("-1"),
+	}
+	create, diags := plan.mapCreateTaskTypeToValues()
+	assert.False(t, diags.HasError())
+	raw, err := json.Marshal(create)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"command": "bin/rails db:migrate", "memory_in_mb": 1024, "log_rate_limit_in_bytes_per_second": -1}`, string(raw))
+
+	plan.DiskQuota = types.StringValue("lots")
+	_, diags = plan.mapCreateTaskTypeToValues()
+	assert.True(t, diags.HasError())
+}
+
+func TestMapTaskValuesToType(t *testing.T) {
+	var task appTask
+	err := json.Unmarshal([]byte(`{
+		"guid": "task-guid",
+		"sequence_id": 3,
+		"name": "migrate",
+		"command": "bin/rails db:migrate",
+		"state": "FAILED",
+		"memory_in_mb": 1024,
+		"disk_in_mb": 512,
+		"log_rate_limit_in_bytes_per_second": -1,
+		"result": {"failure_reason": "Exited with status 1"},
+		"droplet_guid": "droplet-guid",
+		"created_at": "2024-05-01T10:00:00Z",
+		"updated_at": "2024-05-01T10:05:00Z"
+	}`), &task)
+	assert.NoError(t, err)
+	assert.Equal(t, "APP/TASK/migrate", task.sourceType())
+
+	plan := TaskType{App: types.StringValue("app-guid"), Name: types.StringUnknown(), Memory: types.StringValue("1G")}
+	data := plan.mapTaskValuesToType(&task)
+	assert.Equal(t, "task-guid", data.Id.ValueString())
+	assert.Equal(t, "app-guid", data.App.ValueString())
+	assert.Equal(t, "migrate", data.Name.ValueString())
+	assert.Equal(t, "1G", data.Memory.ValueString())
+	assert.Equal(t, int64(3), data.SequenceId.ValueInt64())
+	assert.Equal(t, "Exited with status 1", data.FailureReason.ValueString())
+	assert.Equal(t, "droplet-guid", data.Droplet.ValueString())
+	assert.Equal(t, "2024-05-01T10:05:00Z", data.UpdatedAt.ValueString())
+
+	tasks := mapTasksDatasourceValuesToType([]*appTask{&task})
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, int64(512), tasks[0].DiskInMB.ValueInt64())
+	assert.Equal(t, int64(-1), tasks[0].LogRateLimitPerSecond.ValueInt64())
+	assert.Equal(t, "bin/rails db:migrate", tasks[0].Command.ValueString())
+}
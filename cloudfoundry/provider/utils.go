@@ -3,14 +3,17 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 
 	cfv3client "github.com/cloudfoundry/go-cfclient/v3/client"
 	cfv3resource "github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/terraform-provider-cloudfoundry/internal/appbits"
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -18,6 +21,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/samber/lo"
 )
 
@@ -31,6 +35,24 @@ const (
 
 const defaultTimeout = 20 * time.Minute
 
+// contextWithOptionalTimeout bounds the context by the timeout, a timeout of zero, e.g. because none is configured,
+// leaves it unbounded.
+func contextWithOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// remainingTimeout returns the time left until the deadline of the context, which is set from the configured timeouts,
+// to bound polls which do not stop with the context. Without a deadline the polls are not bounded either.
+func remainingTimeout(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return math.MaxInt64
+}
+
 func datasourceLabelsSchema() *schema.MapAttribute {
 	return &schema.MapAttribute{
 		MarkdownDescription: "The labels associated with Cloud Foundry resources.",
@@ -94,6 +116,56 @@ func guidSchema() *schema.StringAttribute {
 	}
 }
 
+func sourceCodeDigestSchema() *schema.StringAttribute {
+	return &schema.StringAttribute{
+		MarkdownDescription: "The SHA256 digest of the content at `path`, computed during planning. Any change of the content results in an update, without having to set `source_code_hash`.",
+		Computed:            true,
+	}
+}
+
+// modifyPlanSourceCodeDigest plans the digest of the content at the configured path, so that changed bits
+// result in an in-place update. The digest stays unknown if the content does not exist yet at plan time.
+func modifyPlanSourceCodeDigest(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var contentPath types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("path"), &contentPath)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	digest := types.StringUnknown()
+	switch {
+	case contentPath.IsNull():
+		digest = types.StringNull()
+	case contentPath.IsUnknown():
+	default:
+		value, err := appbits.Digest(contentPath.ValueString())
+		if err != nil {
+			tflog.Warn(ctx, "Unable to compute the digest of "+contentPath.ValueString()+" during planning: "+err.Error())
+		} else {
+			digest = types.StringValue(value)
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_code_digest"), digest)...)
+}
+
+// sourceCodeDigestValue returns the planned digest, or computes it if it was not known at plan time.
+func sourceCodeDigestValue(ctx context.Context, planned types.String, contentPath types.String) types.String {
+	if !planned.IsUnknown() {
+		return planned
+	}
+	if contentPath.IsNull() || contentPath.IsUnknown() {
+		return types.StringNull()
+	}
+	value, err := appbits.Digest(contentPath.ValueString())
+	if err != nil {
+		tflog.Warn(ctx, "Unable to compute the digest of "+contentPath.ValueString()+": "+err.Error())
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
 func lastOperationSchema() *schema.SingleNestedAttribute {
 	return &schema.SingleNestedAttribute{
 		MarkdownDescription: "The details of the last operation performed on the resource",
@@ -0,0 +1,20 @@
+data "cloudfoundry_app" "app" {
+  name       = "tf-test-do-not-delete-nodejs"
+  space_name = "tf-space-1"
+  org_name   = "PerformanceTeamBLR"
+}
+
+data "cloudfoundry_app_environment" "env" {
+  app = data.cloudfoundry_app.app.id
+}
+
+# wire the credentials of the bound database into another provider
+locals {
+  db_credentials = jsondecode([for s in data.cloudfoundry_app_environment.env.services : s.credentials if s.label == "postgres"][0])
+}
+
+provider "postgresql" {
+  host     = local.db_credentials.hostname
+  username = local.db_credentials.username
+  password = local.db_credentials.password
+}
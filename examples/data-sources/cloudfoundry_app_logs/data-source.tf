@@ -0,0 +1,20 @@
+data "cloudfoundry_app" "app" {
+  name       = "tf-test-do-not-delete-nodejs"
+  space_name = "tf-space-1"
+  org_name   = "PerformanceTeamBLR"
+}
+
+data "cloudfoundry_app_logs" "startup" {
+  app          = data.cloudfoundry_app.app.id
+  since        = "15m"
+  source_types = ["APP/PROC/WEB"]
+  limit        = 500
+}
+
+# fail the smoke test if the app did not log its startup
+check "app_started" {
+  assert {
+    condition     = anytrue([for e in data.cloudfoundry_app_logs.startup.entries : strcontains(e.message, "Server started")])
+    error_message = "The app did not log its startup."
+  }
+}
@@ -0,0 +1,14 @@
+data "cloudfoundry_app" "app" {
+  name       = "tf-test-do-not-delete-nodejs"
+  space_name = "tf-space-1"
+  org_name   = "PerformanceTeamBLR"
+}
+
+data "cloudfoundry_app_processes" "web" {
+  app  = data.cloudfoundry_app.app.id
+  type = "web"
+}
+
+output "all_instances_running" {
+  value = alltrue([for p in data.cloudfoundry_app_processes.web.processes : p.running_instances == p.instances])
+}
@@ -0,0 +1,13 @@
+data "cloudfoundry_app" "app" {
+  name       = "tf-test-do-not-delete-nodejs"
+  space_name = "tf-space-1"
+  org_name   = "PerformanceTeamBLR"
+}
+
+data "cloudfoundry_app_revisions" "revisions" {
+  app = data.cloudfoundry_app.app.id
+}
+
+output "previous_revision" {
+  value = [for r in reverse(data.cloudfoundry_app_revisions.revisions.revisions) : r.id if r.deployable && !r.deployed][0]
+}
@@ -0,0 +1,14 @@
+data "cloudfoundry_app" "app" {
+  name       = "tf-test-do-not-delete-nodejs"
+  space_name = "tf-space-1"
+  org_name   = "PerformanceTeamBLR"
+}
+
+data "cloudfoundry_tasks" "failed" {
+  app    = data.cloudfoundry_app.app.id
+  states = ["FAILED"]
+}
+
+output "failure_reasons" {
+  value = { for t in data.cloudfoundry_tasks.failed.tasks : t.name => t.failure_reason }
+}
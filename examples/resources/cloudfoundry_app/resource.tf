@@ -107,4 +107,66 @@ resource "cloudfoundry_app" "http-bin-sidecar" {
     }
   ]
   no_route = true
-}
\ No newline at end of file
+}
+
+resource "cloudfoundry_app" "canary" {
+  name             = "tf-test-canary"
+  space_name       = "tf-space-1"
+  org_name         = "PerformanceTeamBLR"
+  path             = zipper_file.fixture.output_path
+  source_code_hash = zipper_file.fixture.output_sha
+  instances        = 4
+  strategy         = "canary"
+  canary_steps = [
+    {
+      instance_weight = 25
+      pause_duration  = 120
+    },
+    {
+      instance_weight = 50
+      pause_duration  = 60
+    },
+  ]
+}
+
+
+resource "cloudfoundry_app" "logged" {
+  name              = "tf-test-logged"
+  space_name        = "tf-space-1"
+  org_name          = "PerformanceTeamBLR"
+  path              = "${path.module}/app"
+  resource_matching = true
+  logging = {
+    source_types = ["STG", "APP"]
+    tail_lines   = 50
+  }
+}
+
+variable "db_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "cloudfoundry_app" "with-secrets" {
+  name       = "tf-test-secrets"
+  space_name = "tf-space-1"
+  org_name   = "PerformanceTeamBLR"
+  path       = "${path.module}/app"
+  strategy   = "rolling"
+  environment = {
+    LOG_LEVEL = "info"
+  }
+  environment_update_action = "restart"
+  sensitive_environment = {
+    DB_PASSWORD = var.db_password
+  }
+}
+
+# deploy a droplet staged once, e.g. in another space, instead of pushing the bits again
+resource "cloudfoundry_app" "promoted" {
+  name       = "tf-test-promoted"
+  space_name = "tf-space-prod"
+  org_name   = "PerformanceTeamBLR"
+  droplet    = cloudfoundry_droplet.http-bin.id
+  strategy   = "rolling"
+}
@@ -0,0 +1,10 @@
+# terraform import cloudfoundry_app_process.<resource_name> <process_guid>
+
+terraform import cloudfoundry_app_process.my_process e3cef997-9ba5-4cb4-b25b-c79faa81a33f
+
+#terraform import using id attribute in import block
+
+import {
+  to = cloudfoundry_app_process.<resource_name>
+  id = "<process_guid>"
+}
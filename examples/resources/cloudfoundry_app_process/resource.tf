@@ -0,0 +1,17 @@
+resource "cloudfoundry_app" "http-bin" {
+  name                 = "http-bin"
+  space_name           = "tf-space-1"
+  org_name             = "PerformanceTeamBLR"
+  docker_image         = "kennethreitz/httpbin"
+  ignore_process_drift = true
+}
+
+# the instances are left to the autoscaler, only the memory and the health check are managed
+resource "cloudfoundry_app_process" "web" {
+  app                        = cloudfoundry_app.http-bin.id
+  type                       = "web"
+  memory                     = "512M"
+  health_check_type          = "http"
+  health_check_http_endpoint = "/status/200"
+  timeout                    = 120
+}
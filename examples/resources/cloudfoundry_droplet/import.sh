@@ -0,0 +1,10 @@
+# terraform import cloudfoundry_droplet.<resource_name> <droplet_guid>
+
+terraform import cloudfoundry_droplet.my_droplet e3cef997-9ba5-4cb4-b25b-c79faa81a33f
+
+#terraform import using id attribute in import block
+
+import {
+  to = cloudfoundry_droplet.<resource_name>
+  id = "<droplet_guid>"
+}
@@ -0,0 +1,10 @@
+# stage the package once
+resource "cloudfoundry_droplet" "http-bin" {
+  package = cloudfoundry_package.http-bin.id
+}
+
+# copy the droplet to another app, e.g. in another space
+resource "cloudfoundry_droplet" "http-bin-prod" {
+  source_droplet = cloudfoundry_droplet.http-bin.id
+  app            = "c4a5c0f6-1f2d-4c38-9a36-5f3f5d0e1b2a"
+}
@@ -0,0 +1,9 @@
+resource "cloudfoundry_package" "http-bin" {
+  app  = cloudfoundry_app.http-bin-build.id
+  path = "${path.module}/assets/http-bin.zip"
+}
+
+resource "cloudfoundry_package" "redis" {
+  app          = "9e0d3d2c-2b4c-4a2e-8b1e-2bd3c2a1f4e7"
+  docker_image = "redis:7.2"
+}
@@ -0,0 +1,13 @@
+# run the database migrations whenever a new droplet is deployed
+resource "cloudfoundry_task" "migrate" {
+  app     = cloudfoundry_app.http-bin.id
+  name    = "migrate"
+  command = "bin/rails db:migrate"
+  memory  = "1G"
+  triggers = {
+    droplet = cloudfoundry_droplet.http-bin.id
+  }
+  timeouts = {
+    create = "30m"
+  }
+}
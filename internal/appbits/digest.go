@@ -0,0 +1,70 @@
+package appbits
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// Digest returns the hex encoded SHA256 digest of the app bits at the path. For a zip file it is the digest of the file,
+// for a directory it covers the names, executable bits and contents of all files which are not ignored, so it changes
+// exactly when the archive created by ZipDirectory changes.
+func Digest(appPath string) (string, error) {
+	isDir, err := IsDirectory(appPath)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	if !isDir {
+		if err := hashFile(hash, appPath); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+	matcher, err := LoadIgnoreMatcher(appPath)
+	if err != nil {
+		return "", err
+	}
+	err = WalkDirectory(appPath, matcher, func(relPath string, absPath string, entry fs.DirEntry) error {
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.IsDir():
+			_, err = fmt.Fprintf(hash, "d %s\x00", relPath)
+			return err
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(absPath)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(hash, "l %s\x00%s\x00", relPath, target)
+			return err
+		default:
+			fileHash := sha256.New()
+			if err := hashFile(fileHash, absPath); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(hash, "f %s\x00%o\x00%x\x00", relPath, normalizedMode(info.Mode()), fileHash.Sum(nil))
+			return err
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
@@ -0,0 +1,73 @@
+package appbits
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDigest(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		".cfignore":    "*.log\n",
+		"index.js":     "console.log('hello')",
+		"lib/a.js":     "module.exports = {}",
+		"debug.log":    "log",
+		"manifest.yml": "applications: []",
+	}
+
+	t.Run("directory digest only changes with relevant content", func(t *testing.T) {
+		dir := writeTestApp(t, files)
+		digest, err := Digest(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		same, err := Digest(writeTestApp(t, files))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if digest != same {
+			t.Errorf("digests of identical directories differ: %s != %s", digest, same)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, "debug.log"), []byte("more log"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		ignoredChange, err := Digest(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if digest != ignoredChange {
+			t.Error("digest changed with an ignored file")
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, "lib", "a.js"), []byte("module.exports = 1"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		changed, err := Digest(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if digest == changed {
+			t.Error("digest did not change with the content")
+		}
+	})
+
+	t.Run("file digest", func(t *testing.T) {
+		dir := writeTestApp(t, map[string]string{"app.zip": "zip"})
+		digest, err := Digest(filepath.Join(dir, "app.zip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "4a70fe9aa6436e02c2dea340fbd1e352e4ef2d8ce6ca52ad25d4b95471fc8bf2"; digest != expected {
+			t.Errorf("unexpected digest %s, expected %s", digest, expected)
+		}
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		if _, err := Digest(filepath.Join(t.TempDir(), "missing")); err == nil {
+			t.Error("expected an error for a missing path")
+		}
+	})
+}
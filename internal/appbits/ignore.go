@@ -0,0 +1,143 @@
+package appbits
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const CfIgnoreFile = ".cfignore"
+
+// DefaultIgnoreLines are the patterns the cf CLI excludes from every app upload.
+// ref - https://github.com/cloudfoundry/cli/blob/v8.7.10/actor/sharedaction/resource.go
+var DefaultIgnoreLines = []string{
+	".cfignore",
+	"/manifest.yml",
+	".gitignore",
+	".git",
+	".hg",
+	".svn",
+	"_darcs",
+	".DS_Store",
+}
+
+type ignorePattern struct {
+	segments []string
+	negate   bool
+	anchored bool
+	dirOnly  bool
+}
+
+// IgnoreMatcher decides which files are left out of an app upload, following the .cfignore semantics
+// of the cf CLI which are the ones of .gitignore files.
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+// NewIgnoreMatcher parses the given .cfignore lines. Blank lines and comments are skipped.
+func NewIgnoreMatcher(lines []string) *IgnoreMatcher {
+	m := &IgnoreMatcher{}
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		// a pattern with a slash at the beginning or in the middle is relative to the app root
+		if strings.Contains(line, "/") {
+			p.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		p.segments = strings.Split(line, "/")
+		m.patterns = append(m.patterns, p)
+	}
+	return m
+}
+
+// LoadIgnoreMatcher returns a matcher with the default cf CLI exclusions and the patterns of the .cfignore file in dir, if any.
+func LoadIgnoreMatcher(dir string) (*IgnoreMatcher, error) {
+	lines := append([]string{}, DefaultIgnoreLines...)
+	file, err := os.Open(filepath.Join(dir, CfIgnoreFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return NewIgnoreMatcher(lines), nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewIgnoreMatcher(lines), nil
+}
+
+// Ignored reports whether the slash separated path relative to the app root is excluded. The last matching pattern wins.
+func (m *IgnoreMatcher) Ignored(relPath string, isDir bool) bool {
+	segments := strings.Split(strings.Trim(relPath, "/"), "/")
+	ignored := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.matches(segments) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+func (p ignorePattern) matches(segments []string) bool {
+	if p.anchored {
+		return matchSegments(p.segments, segments)
+	}
+	// an unanchored pattern matches at any depth
+	for i := range segments {
+		if matchSegments(p.segments, segments[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches all path segments against the pattern segments, where '**' matches any number of segments.
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], segments[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,52 @@
+package appbits
+
+import (
+	"testing"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}
+
+	matcher := NewIgnoreMatcher(append(DefaultIgnoreLines,
+		"# comment",
+		"",
+		"*.log",
+		"!keep.log",
+		"/tmp",
+		"build/",
+		"docs/**/*.md",
+		"node_modules",
+	))
+
+	testCases := map[string]testCase{
+		"default-git-dir":              {path: ".git", isDir: true, ignored: true},
+		"default-nested-ds-store":      {path: "src/.DS_Store", ignored: true},
+		"default-root-manifest":        {path: "manifest.yml", ignored: true},
+		"default-nested-manifest-kept": {path: "config/manifest.yml", ignored: false},
+		"glob-at-any-depth":            {path: "logs/app/server.log", ignored: true},
+		"negated-pattern":              {path: "logs/keep.log", ignored: false},
+		"anchored-pattern":             {path: "tmp", isDir: true, ignored: true},
+		"anchored-pattern-nested-kept": {path: "src/tmp", isDir: true, ignored: false},
+		"dir-only-pattern-dir":         {path: "src/build", isDir: true, ignored: true},
+		"dir-only-pattern-file-kept":   {path: "src/build", isDir: false, ignored: false},
+		"double-star-zero-dirs":        {path: "docs/index.md", ignored: true},
+		"double-star-many-dirs":        {path: "docs/a/b/c.md", ignored: true},
+		"double-star-other-ext-kept":   {path: "docs/a/b/c.txt", ignored: false},
+		"nested-dir-name":              {path: "web/node_modules", isDir: true, ignored: true},
+		"regular-file-kept":            {path: "src/index.js", ignored: false},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := matcher.Ignored(test.path, test.isDir); got != test.ignored {
+				t.Errorf("Ignored(%q, %t) = %t, expected %t", test.path, test.isDir, got, test.ignored)
+			}
+		})
+	}
+}
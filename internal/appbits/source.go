@@ -0,0 +1,189 @@
+package appbits
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Resource describes a file of the app bits as used by the CF resource matching API.
+type Resource struct {
+	Path string
+	SHA1 string
+	Size int64
+	Mode fs.FileMode
+}
+
+type sourceEntry struct {
+	name string
+	mode fs.FileMode
+	size int64
+	open func() (io.ReadCloser, error)
+}
+
+// Source gives access to the files of the app bits at a path, which is either a zip archive or a directory.
+// For a directory the .cfignore file and the default cf CLI exclusions are applied.
+type Source struct {
+	entries []sourceEntry
+	closer  io.Closer
+}
+
+// OpenSource collects the entries of the zip archive or directory at the app path. The source must be closed after use.
+func OpenSource(appPath string) (*Source, error) {
+	isDir, err := IsDirectory(appPath)
+	if err != nil {
+		return nil, err
+	}
+	if isDir {
+		return openDirectorySource(appPath)
+	}
+	return openZipSource(appPath)
+}
+
+func openDirectorySource(dir string) (*Source, error) {
+	matcher, err := LoadIgnoreMatcher(dir)
+	if err != nil {
+		return nil, err
+	}
+	source := &Source{}
+	err = WalkDirectory(dir, matcher, func(relPath string, absPath string, entry fs.DirEntry) error {
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		e := sourceEntry{name: relPath}
+		switch {
+		case info.IsDir():
+			e.mode = fs.ModeDir | 0755
+		case info.Mode()&fs.ModeSymlink != 0:
+			e.mode = fs.ModeSymlink | 0777
+			e.open = func() (io.ReadCloser, error) {
+				target, err := os.Readlink(absPath)
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(strings.NewReader(filepath.ToSlash(target))), nil
+			}
+		default:
+			e.mode = normalizedMode(info.Mode())
+			e.size = info.Size()
+			e.open = func() (io.ReadCloser, error) {
+				return os.Open(absPath)
+			}
+		}
+		source.entries = append(source.entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func openZipSource(zipPath string) (*Source, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	source := &Source{closer: reader}
+	for _, f := range reader.File {
+		e := sourceEntry{name: strings.TrimSuffix(f.Name, "/")}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir() || strings.HasSuffix(f.Name, "/"):
+			e.mode = fs.ModeDir | 0755
+		case mode&fs.ModeSymlink != 0:
+			e.mode = fs.ModeSymlink | 0777
+			e.open = f.Open
+		default:
+			e.mode = normalizedMode(mode)
+			e.size = int64(f.UncompressedSize64)
+			e.open = f.Open
+		}
+		source.entries = append(source.entries, e)
+	}
+	sort.SliceStable(source.entries, func(i, j int) bool {
+		return source.entries[i].name < source.entries[j].name
+	})
+	return source, nil
+}
+
+// Close releases the zip archive of the source, if any.
+func (s *Source) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// Resources returns the regular files of the source with their SHA1 checksums.
+func (s *Source) Resources() ([]Resource, error) {
+	var resources []Resource
+	for _, e := range s.entries {
+		if !e.mode.IsRegular() {
+			continue
+		}
+		hash := sha1.New()
+		if err := copyEntry(hash, e); err != nil {
+			return nil, err
+		}
+		resources = append(resources, Resource{
+			Path: e.name,
+			SHA1: hex.EncodeToString(hash.Sum(nil)),
+			Size: e.size,
+			Mode: e.mode,
+		})
+	}
+	return resources, nil
+}
+
+// Zip creates a deterministic in-memory zip archive of the source, leaving out the regular files whose paths are in exclude.
+func (s *Source) Zip(exclude map[string]bool) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, e := range s.entries {
+		if e.mode.IsRegular() && exclude[e.name] {
+			continue
+		}
+		header := &zip.FileHeader{
+			Name:     e.name,
+			Method:   zip.Deflate,
+			Modified: zipModTime,
+		}
+		if e.mode.IsDir() {
+			header.Name += "/"
+			header.Method = zip.Store
+		}
+		header.SetMode(e.mode)
+		w, err := writer.CreateHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		if e.open != nil {
+			if err := copyEntry(w, e); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func copyEntry(w io.Writer, e sourceEntry) error {
+	reader, err := e.open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, err = io.Copy(w, reader)
+	return err
+}
@@ -0,0 +1,84 @@
+package appbits
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTestApp(t, map[string]string{
+		".cfignore":    "*.log\n",
+		"app.log":      "log",
+		"index.js":     "hello",
+		"lib/empty.js": "",
+	})
+
+	t.Run("resources of a directory", func(t *testing.T) {
+		source, err := OpenSource(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer source.Close()
+		resources, err := source.Resources()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []Resource{
+			{Path: "index.js", SHA1: "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d", Size: 5, Mode: 0644},
+			{Path: "lib/empty.js", SHA1: "da39a3ee5e6b4b0d3255bfef95601890afd80709", Size: 0, Mode: 0644},
+		}
+		if !reflect.DeepEqual(resources, expected) {
+			t.Errorf("unexpected resources %v, expected %v", resources, expected)
+		}
+	})
+
+	t.Run("resources of a zip archive", func(t *testing.T) {
+		data, err := ZipDirectory(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zipPath := filepath.Join(t.TempDir(), "app.zip")
+		if err := os.WriteFile(zipPath, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		zipSource, err := OpenSource(zipPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zipSource.Close()
+		dirSource, err := OpenSource(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromZip, err := zipSource.Resources()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromDir, err := dirSource.Resources()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(fromZip, fromDir) {
+			t.Errorf("zip resources %v differ from directory resources %v", fromZip, fromDir)
+		}
+	})
+
+	t.Run("zip without excluded files", func(t *testing.T) {
+		source, err := OpenSource(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := source.Zip(map[string]bool{"index.js": true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{"lib/", "lib/empty.js"}
+		if got := zipEntries(t, data); !reflect.DeepEqual(got, expected) {
+			t.Errorf("unexpected zip entries %v, expected %v", got, expected)
+		}
+	})
+}
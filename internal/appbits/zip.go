@@ -0,0 +1,64 @@
+package appbits
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// zipModTime is the fixed modification time of all archive entries, it keeps the archive independent of checkouts and builds.
+var zipModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// IsDirectory reports whether the app path points to a directory rather than a zip archive.
+func IsDirectory(appPath string) (bool, error) {
+	info, err := os.Stat(appPath)
+	if err != nil {
+		return false, err
+	}
+	return info.IsDir(), nil
+}
+
+// ZipDirectory creates an in-memory zip archive of the directory for an app upload. Files excluded by the default cf CLI
+// exclusions or the .cfignore file of the directory are left out. The archive is deterministic: entries are sorted,
+// timestamps are fixed and permissions are normalized, so it only changes when the content of the app changes.
+func ZipDirectory(dir string) ([]byte, error) {
+	source, err := openDirectorySource(dir)
+	if err != nil {
+		return nil, err
+	}
+	return source.Zip(nil)
+}
+
+// WalkDirectory calls fn in lexical order for every file and directory below dir which is not ignored by the matcher.
+// relPath is slash separated and relative to dir, ignored directories are skipped entirely.
+func WalkDirectory(dir string, matcher *IgnoreMatcher, fn func(relPath string, absPath string, entry fs.DirEntry) error) error {
+	return filepath.WalkDir(dir, func(absPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, absPath)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		relPath := filepath.ToSlash(rel)
+		if matcher.Ignored(relPath, entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(relPath, absPath, entry)
+	})
+}
+
+// normalizedMode keeps only whether a file is executable, which is what matters for the app container.
+func normalizedMode(mode fs.FileMode) fs.FileMode {
+	if mode.Perm()&0111 != 0 {
+		return 0755
+	}
+	return 0644
+}
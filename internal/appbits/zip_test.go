@@ -0,0 +1,114 @@
+package appbits
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeTestApp(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func zipEntries(t *testing.T, data []byte) []string {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestZipDirectory(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		".cfignore":         "*.log\n/secrets\n",
+		".git/HEAD":         "ref: refs/heads/main",
+		"manifest.yml":      "applications: []",
+		"package.json":      "{}",
+		"secrets/key.pem":   "secret",
+		"src/app.log":       "log",
+		"src/index.js":      "console.log('hello')",
+		"src/lib/helper.js": "module.exports = {}",
+	}
+
+	t.Run("excludes ignored files", func(t *testing.T) {
+		data, err := ZipDirectory(writeTestApp(t, files))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{"package.json", "src/", "src/index.js", "src/lib/", "src/lib/helper.js"}
+		if got := zipEntries(t, data); !reflect.DeepEqual(got, expected) {
+			t.Errorf("unexpected zip entries %v, expected %v", got, expected)
+		}
+	})
+
+	t.Run("is deterministic", func(t *testing.T) {
+		first := writeTestApp(t, files)
+		second := writeTestApp(t, files)
+		later := time.Now().Add(time.Hour)
+		if err := os.Chtimes(filepath.Join(second, "src", "index.js"), later, later); err != nil {
+			t.Fatal(err)
+		}
+		firstZip, err := ZipDirectory(first)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secondZip, err := ZipDirectory(second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(firstZip, secondZip) {
+			t.Error("zip archives of identical content differ")
+		}
+
+		if err := os.WriteFile(filepath.Join(second, "src", "index.js"), []byte("changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		changedZip, err := ZipDirectory(second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(firstZip, changedZip) {
+			t.Error("zip archive did not change with the content")
+		}
+	})
+
+	t.Run("keeps executable bit", func(t *testing.T) {
+		dir := writeTestApp(t, map[string]string{"start.sh": "#!/bin/sh"})
+		if err := os.Chmod(filepath.Join(dir, "start.sh"), 0700); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ZipDirectory(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mode := reader.File[0].Mode().Perm(); mode != 0755 {
+			t.Errorf("unexpected mode %v", mode)
+		}
+	})
+}
@@ -106,6 +106,45 @@ func (a *DefaultApiService) GetMtaOperation(ctx context.Context, spaceGuid strin
 	return operation, httpResponse, err
 }
 
+/*
+Retrieves the actions which can be executed over a Multi-Target Application operation, e.g. retry or abort.
+*/
+func (a *DefaultApiService) GetMtaOperationActions(ctx context.Context, spaceGuid string, operationId string) ([]string, *http.Response, error) {
+	var (
+		actions []string
+		request = newRequestInfo()
+	)
+	request.path = a.client.cfg.BasePath + "/api/v1/spaces/" + spaceGuid + "/operations/" + operationId + "/actions"
+	httpResponse, err := a.client.get(ctx, request, &actions)
+	return actions, httpResponse, err
+}
+
+/*
+Retrieves the logs of a Multi-Target Application operation.
+*/
+func (a *DefaultApiService) GetMtaOperationLogs(ctx context.Context, spaceGuid string, operationId string) ([]Log, *http.Response, error) {
+	var (
+		logs    []Log
+		request = newRequestInfo()
+	)
+	request.path = a.client.cfg.BasePath + "/api/v1/spaces/" + spaceGuid + "/operations/" + operationId + "/logs"
+	httpResponse, err := a.client.get(ctx, request, &logs)
+	return logs, httpResponse, err
+}
+
+/*
+Retrieves the content of a log of a Multi-Target Application operation.
+*/
+func (a *DefaultApiService) GetMtaOperationLogContent(ctx context.Context, spaceGuid string, operationId string, logId string) (string, *http.Response, error) {
+	var (
+		content string
+		request = newRequestInfo()
+	)
+	request.path = a.client.cfg.BasePath + "/api/v1/spaces/" + spaceGuid + "/operations/" + operationId + "/logs/" + logId + "/content"
+	httpResponse, err := a.client.get(ctx, request, &content)
+	return content, httpResponse, err
+}
+
 /*
 Retrieves Multi-Target Application operations.
 */
@@ -206,9 +206,29 @@ func (c *APIClient) decode(v interface{}, b []byte, contentType string) (err err
 		}
 		return nil
 	}
+	if strings.Contains(contentType, "text/plain") {
+		if s, ok := stringTarget(v); ok {
+			*s = string(b)
+			return nil
+		}
+	}
 	return errors.New("undefined response type")
 }
 
+// stringTarget returns the string a text response is decoded into, the callers wrap it into pointers to interfaces.
+func stringTarget(v interface{}) (*string, bool) {
+	for {
+		switch target := v.(type) {
+		case *string:
+			return target, true
+		case *interface{}:
+			v = *target
+		default:
+			return nil, false
+		}
+	}
+}
+
 func (c *APIClient) returnResponse(resp *http.Response, returnValue interface{}, varBody []byte) error {
 	if resp.StatusCode == 204 {
 		return nil
@@ -0,0 +1,297 @@
+package mta
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ref - https://github.com/SAP/cloud-mta/blob/master/validations/mta_schema.yaml
+var (
+	descriptorKeys = []string{
+		"_schema-version", "ID", "version", "description", "provider", "copyright", "namespace", "modules",
+		"resources", "parameters", "parameters-metadata", "module-types", "resource-types", "includes",
+		"build-parameters",
+	}
+	extensionDescriptorKeys = []string{
+		"_schema-version", "ID", "extends", "version", "description", "provider", "modules", "resources",
+		"parameters", "parameters-metadata",
+	}
+	moduleKeys = []string{
+		"name", "type", "path", "description", "properties", "properties-metadata", "parameters",
+		"parameters-metadata", "includes", "requires", "provides", "build-parameters", "hooks", "deployed-after",
+	}
+	extensionModuleKeys = []string{
+		"name", "properties", "properties-metadata", "parameters", "parameters-metadata", "requires", "provides",
+		"hooks",
+	}
+	resourceKeys = []string{
+		"name", "type", "description", "properties", "properties-metadata", "parameters", "parameters-metadata",
+		"includes", "optional", "active", "requires", "processed-after",
+	}
+	extensionResourceKeys = []string{
+		"name", "properties", "properties-metadata", "parameters", "parameters-metadata", "active", "requires",
+	}
+	// mappingKeys are the keys whose values have to be mappings.
+	mappingKeys = []string{
+		"parameters", "parameters-metadata", "properties", "properties-metadata", "build-parameters",
+	}
+	// namedListKeys are the keys whose values have to be lists of mappings with a name.
+	namedListKeys = []string{
+		"requires", "provides", "includes", "hooks", "module-types", "resource-types",
+	}
+	// scalarKeys are the keys whose values have to be scalars.
+	scalarKeys = []string{
+		"_schema-version", "ID", "version", "extends", "namespace", "name", "type", "path", "description",
+		"provider", "copyright",
+	}
+
+	schemaVersionRegex = regexp.MustCompile(`^[23](\.[0-9]+){0,2}$`)
+	mtaIdRegex         = regexp.MustCompile(`^[A-Za-z0-9_\-.]+$`)
+	mtaVersionRegex    = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+([-+][0-9A-Za-z.+-]*)?$`)
+	yamlErrorRegex     = regexp.MustCompile(`^yaml: line ([0-9]+): (.*)$`)
+)
+
+// DescriptorElement is a module or resource of an MTA descriptor.
+type DescriptorElement struct {
+	Name string
+	Line int
+}
+
+// DescriptorError is an error in an MTA descriptor, the line is 0 if the error does not refer to a line.
+type DescriptorError struct {
+	Source  string
+	Line    int
+	Message string
+}
+
+func (e DescriptorError) Error() string {
+	if e.Line == 0 {
+		return e.Source + ": " + e.Message
+	}
+	return fmt.Sprintf("%s, line %d: %s", e.Source, e.Line, e.Message)
+}
+
+// descriptorParser collects the errors of a descriptor.
+type descriptorParser struct {
+	source    string
+	extension bool
+	errs      []error
+}
+
+func (p *descriptorParser) addError(line int, format string, args ...any) {
+	p.errs = append(p.errs, DescriptorError{Source: p.source, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// ParseMtaDescriptor parses and validates the deployment descriptor of an MTA or, if extension is set, an extension
+// descriptor against the MTA schema. The errors are DescriptorErrors with the line of the YAML they refer to.
+func ParseMtaDescriptor(source string, content []byte, extension bool) (MtaDescriptor, error) {
+	p := descriptorParser{source: source, extension: extension}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		message := strings.TrimPrefix(err.Error(), "yaml: ")
+		line := 0
+		if match := yamlErrorRegex.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+			message = match[2]
+		}
+		p.addError(line, "%s", message)
+		return MtaDescriptor{}, errors.Join(p.errs...)
+	}
+	if len(document.Content) == 0 {
+		p.addError(0, "the descriptor is empty")
+		return MtaDescriptor{}, errors.Join(p.errs...)
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		p.addError(root.Line, "the descriptor has to be a mapping")
+		return MtaDescriptor{}, errors.Join(p.errs...)
+	}
+	p.checkDuplicateKeys(root)
+
+	var descriptor MtaDescriptor
+	if p.extension {
+		p.checkMapping(root, "descriptor", extensionDescriptorKeys, "_schema-version", "ID", "extends")
+	} else {
+		p.checkMapping(root, "descriptor", descriptorKeys, "_schema-version", "ID", "version")
+	}
+	if value := mappingValue(root, "_schema-version"); value != nil && value.Kind == yaml.ScalarNode {
+		descriptor.SchemaVersion = value.Value
+		if !schemaVersionRegex.MatchString(value.Value) {
+			p.addError(value.Line, "unsupported _schema-version %q, expected a version 2 or 3", value.Value)
+		}
+	}
+	if value := mappingValue(root, "ID"); value != nil && value.Kind == yaml.ScalarNode {
+		descriptor.ID = value.Value
+		if !mtaIdRegex.MatchString(value.Value) {
+			p.addError(value.Line, "invalid ID %q, it may only contain letters, digits, '-', '_' and '.'", value.Value)
+		}
+	}
+	if value := mappingValue(root, "version"); value != nil && value.Kind == yaml.ScalarNode {
+		descriptor.Version = value.Value
+		if !p.extension && !mtaVersionRegex.MatchString(value.Value) {
+			p.addError(value.Line, "invalid version %q, expected a semantic version like 1.0.0", value.Value)
+		}
+	}
+	if value := mappingValue(root, "namespace"); value != nil && value.Kind == yaml.ScalarNode {
+		descriptor.Namespace = value.Value
+	}
+	if value := mappingValue(root, "extends"); value != nil && value.Kind == yaml.ScalarNode {
+		descriptor.Extends = value.Value
+		descriptor.extendsLine = value.Line
+	}
+
+	elementKeys := [2][]string{moduleKeys, resourceKeys}
+	if p.extension {
+		elementKeys = [2][]string{extensionModuleKeys, extensionResourceKeys}
+	}
+	descriptor.Modules = p.parseElements(mappingValue(root, "modules"), "module", elementKeys[0])
+	descriptor.Resources = p.parseElements(mappingValue(root, "resources"), "resource", elementKeys[1])
+	descriptor.source = source
+
+	return descriptor, errors.Join(p.errs...)
+}
+
+// parseElements validates the modules or resources of a descriptor and returns their names.
+func (p *descriptorParser) parseElements(node *yaml.Node, kind string, keys []string) []DescriptorElement {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var elements []DescriptorElement
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			p.addError(item.Line, "a %s has to be a mapping", kind)
+			continue
+		}
+		required := []string{"name"}
+		if kind == "module" && !p.extension {
+			required = append(required, "type")
+		}
+		p.checkMapping(item, kind, keys, required...)
+		name := mappingValue(item, "name")
+		if name == nil || name.Kind != yaml.ScalarNode {
+			continue
+		}
+		if i := slices.IndexFunc(elements, func(e DescriptorElement) bool { return e.Name == name.Value }); i >= 0 {
+			p.addError(name.Line, "%s %q is already defined at line %d", kind, name.Value, elements[i].Line)
+			continue
+		}
+		elements = append(elements, DescriptorElement{Name: name.Value, Line: name.Line})
+	}
+	return elements
+}
+
+// checkMapping validates the keys of a mapping of the descriptor and the kind of their values.
+func (p *descriptorParser) checkMapping(node *yaml.Node, kind string, keys []string, required ...string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch {
+		case !slices.Contains(keys, key.Value):
+			p.addError(key.Line, "unknown property %q of the %s", key.Value, kind)
+		case slices.Contains(scalarKeys, key.Value) && value.Kind != yaml.ScalarNode:
+			p.addError(value.Line, "%q has to be a single value", key.Value)
+		case slices.Contains(mappingKeys, key.Value) && value.Kind != yaml.MappingNode && !isNull(value):
+			p.addError(value.Line, "%q has to be a mapping", key.Value)
+		case (key.Value == "modules" || key.Value == "resources" || key.Value == "deployed-after" ||
+			key.Value == "processed-after") && value.Kind != yaml.SequenceNode && !isNull(value):
+			p.addError(value.Line, "%q has to be a list", key.Value)
+		case slices.Contains(namedListKeys, key.Value):
+			p.checkNamedList(key.Value, value)
+		}
+	}
+	for _, key := range required {
+		if mappingValue(node, key) == nil {
+			p.addError(node.Line, "the %s is missing the required property %q", kind, key)
+		}
+	}
+}
+
+// checkNamedList validates that the value is a list of mappings with a name.
+func (p *descriptorParser) checkNamedList(key string, value *yaml.Node) {
+	if isNull(value) {
+		return
+	}
+	if value.Kind != yaml.SequenceNode {
+		p.addError(value.Line, "%q has to be a list", key)
+		return
+	}
+	for _, item := range value.Content {
+		if item.Kind != yaml.MappingNode || mappingValue(item, "name") == nil {
+			p.addError(item.Line, "the entries of %q have to be mappings with a name", key)
+		}
+	}
+}
+
+// checkDuplicateKeys reports the keys which are defined more than once in a mapping of the descriptor.
+func (p *descriptorParser) checkDuplicateKeys(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		lines := map[string]int{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if line, ok := lines[key.Value]; ok {
+				p.addError(key.Line, "property %q is already defined at line %d", key.Value, line)
+				continue
+			}
+			lines[key.Value] = key.Line
+		}
+	}
+	for _, child := range node.Content {
+		p.checkDuplicateKeys(child)
+	}
+}
+
+// mappingValue returns the value of the key in the mapping, or nil if it is not set.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// ValidateExtensionDescriptor checks that the extension descriptor extends the MTA of the deployment descriptor or
+// one of the other extension descriptors, and that the modules and resources it extends are defined by the MTA.
+func ValidateExtensionDescriptor(descriptor MtaDescriptor, extension MtaDescriptor, extensions []MtaDescriptor) error {
+	var errs []error
+	addError := func(line int, format string, args ...any) {
+		errs = append(errs, DescriptorError{Source: extension.source, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	extendable := []string{descriptor.ID}
+	for _, other := range extensions {
+		if other.ID != extension.ID {
+			extendable = append(extendable, other.ID)
+		}
+	}
+	if !slices.Contains(extendable, extension.Extends) {
+		addError(extension.extendsLine, "extends %q, which is neither the ID of the MTA %q nor of another extension descriptor", extension.Extends, descriptor.ID)
+	}
+
+	checks := []struct {
+		kind     string
+		extended []DescriptorElement
+		defined  []DescriptorElement
+	}{
+		{"module", extension.Modules, descriptor.Modules},
+		{"resource", extension.Resources, descriptor.Resources},
+	}
+	for _, check := range checks {
+		for _, element := range check.extended {
+			if !slices.ContainsFunc(check.defined, func(e DescriptorElement) bool { return e.Name == element.Name }) {
+				addError(element.Line, "%s %q is not defined by the MTA %q", check.kind, element.Name, descriptor.ID)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
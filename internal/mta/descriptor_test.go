@@ -0,0 +1,179 @@
+package mta
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGetMtaDescriptorFromArchive(t *testing.T) {
+	descriptor, err := GetMtaDescriptorFromArchive("../../assets/my-mta_1.0.0.mtar")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if descriptor.ID != "my-mta" || descriptor.Version != "1.0.0" {
+		t.Errorf("unexpected descriptor %+v", descriptor)
+	}
+	if len(descriptor.Modules) != 1 || descriptor.Modules[0].Name != "my-app" {
+		t.Errorf("unexpected modules %+v", descriptor.Modules)
+	}
+	if len(descriptor.Resources) != 1 || descriptor.Resources[0].Name != "my-service" {
+		t.Errorf("unexpected resources %+v", descriptor.Resources)
+	}
+
+	if _, err := GetMtaDescriptorFromArchive("../../assets/a.cf.app.mtar"); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	_, err = GetMtaDescriptorFromArchive("../../assets/provider-config-local.txt")
+	var descriptorErr DescriptorError
+	if err == nil || errors.As(err, &descriptorErr) {
+		t.Errorf("expected an error reading the archive, got %v", err)
+	}
+}
+
+func TestParseMtaDescriptorErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		extension bool
+		expected  []string
+	}{
+		{
+			name:     "syntax error",
+			content:  "ID: my-mta\nmodules:\n- name: a\n type: b\n",
+			expected: []string{"mta.yaml, line 3: did not find expected key"},
+		},
+		{
+			name:     "empty",
+			content:  "",
+			expected: []string{"mta.yaml: the descriptor is empty"},
+		},
+		{
+			name:    "missing and unknown properties",
+			content: "_schema-version: 3.3.0\nID: my-mta\nmodlues:\n- name: a\n",
+			expected: []string{
+				`mta.yaml, line 3: unknown property "modlues" of the descriptor`,
+				`mta.yaml, line 1: the descriptor is missing the required property "version"`,
+			},
+		},
+		{
+			name:    "invalid values",
+			content: "_schema-version: 1.0\nID: my mta\nversion: latest\nparameters: [a]\n",
+			expected: []string{
+				`line 4: "parameters" has to be a mapping`,
+				`line 1: unsupported _schema-version "1.0"`,
+				`line 2: invalid ID "my mta"`,
+				`line 3: invalid version "latest"`,
+			},
+		},
+		{
+			name: "modules",
+			content: `_schema-version: 3.3.0
+ID: my-mta
+version: 1.0.0
+modules:
+- name: a
+  type: application
+  requires: my-service
+- name: a
+  type: application
+  typo: true
+- type: application
+`,
+			expected: []string{
+				`line 7: "requires" has to be a list`,
+				`line 10: unknown property "typo" of the module`,
+				`line 8: module "a" is already defined at line 5`,
+				`line 11: the module is missing the required property "name"`,
+			},
+		},
+		{
+			name:    "duplicate keys",
+			content: "_schema-version: 3.3.0\nID: my-mta\nversion: 1.0.0\nID: other\n",
+			expected: []string{
+				`line 4: property "ID" is already defined at line 2`,
+			},
+		},
+		{
+			name:      "extension",
+			content:   "_schema-version: 3.3.0\nID: my-mta-prod\nmodules:\n- name: a\n  type: application\n",
+			extension: true,
+			expected: []string{
+				`line 1: the descriptor is missing the required property "extends"`,
+				`line 5: unknown property "type" of the module`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseMtaDescriptor("mta.yaml", []byte(test.content), test.extension)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			var descriptorErr DescriptorError
+			if !errors.As(err, &descriptorErr) {
+				t.Errorf("expected a DescriptorError, got %T", err)
+			}
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(test.expected) {
+				t.Fatalf("expected %d errors, got %q", len(test.expected), err.Error())
+			}
+			for i, expected := range test.expected {
+				if !strings.Contains(lines[i], expected) {
+					t.Errorf("expected error %q to contain %q", lines[i], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateExtensionDescriptor(t *testing.T) {
+	descriptor, err := GetMtaDescriptorFromArchive("../../assets/my-mta_1.0.0.mtar")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var extensions []MtaDescriptor
+	for _, file := range []string{"../../assets/prod.mtaext", "../../assets/prod-scale-vertically.mtaext"} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		extension, err := ParseMtaDescriptor(file, content, true)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		extensions = append(extensions, extension)
+	}
+	for _, extension := range extensions {
+		if err := ValidateExtensionDescriptor(descriptor, extension, extensions); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+
+	invalid, err := ParseMtaDescriptor("dev.mtaext", []byte(`_schema-version: 3.3.0
+ID: my-mta-dev
+extends: my-mtaa
+modules:
+- name: my-ap
+resources:
+- name: my-service
+`), true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	err = ValidateExtensionDescriptor(descriptor, invalid, append(extensions, invalid))
+	expected := []string{
+		`dev.mtaext, line 3: extends "my-mtaa", which is neither the ID of the MTA "my-mta" nor of another extension descriptor`,
+		`dev.mtaext, line 5: module "my-ap" is not defined by the MTA "my-mta"`,
+	}
+	if err == nil || err.Error() != strings.Join(expected, "\n") {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+
+	self, _ := ParseMtaDescriptor("self.mtaext", []byte("_schema-version: 3.3.0\nID: self\nextends: self\n"), true)
+	if err := ValidateExtensionDescriptor(descriptor, self, []MtaDescriptor{self}); err == nil {
+		t.Error("expected an error for an extension descriptor extending itself")
+	}
+}
@@ -9,8 +9,6 @@ import (
 	"net/http"
 	"strings"
 	"time"
-
-	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -19,15 +17,66 @@ const (
 	AbortedState          string = "ABORTED"
 )
 
+// ErrOperationFailed is wrapped by the error of PollMtaOperation if the operation entered the ERROR state, in which
+// it waits for an action, e.g. to retry or abort it.
+var ErrOperationFailed = errors.New("operation failed")
+
+// Backoff configures the interval between two polls of an operation or job, which grows by the factor after each poll
+// up to the maximum interval.
+type Backoff struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Factor      float64
+}
+
+// DefaultBackoff polls every 2 seconds at first and slows down to every 10 seconds for long running operations. It is
+// used unless the polling of the MTA resource is configured.
+var DefaultBackoff = Backoff{
+	Interval:    2 * time.Second,
+	MaxInterval: 10 * time.Second,
+	Factor:      1.5,
+}
+
+// next returns the interval to wait after the given one.
+func (b Backoff) next(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * b.Factor)
+	if next > b.MaxInterval {
+		return b.MaxInterval
+	}
+	if next < b.Interval {
+		return b.Interval
+	}
+	return next
+}
+
+// wait sleeps for the interval, unless the context is cancelled or its deadline is exceeded first.
+func wait(ctx context.Context, interval time.Duration) error {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 type MtaDescriptor struct {
-	SchemaVersion string `yaml:"_schema-version,omitempty"`
-	ID            string `yaml:"ID,omitempty"`
-	Version       string `yaml:"version,omitempty"`
-	Namespace     string `yaml:"namespace,omitempty"`
+	SchemaVersion string
+	ID            string
+	Version       string
+	Namespace     string
+	Extends       string
+	Modules       []DescriptorElement
+	Resources     []DescriptorElement
+
+	source      string
+	extendsLine int
 }
 
 // ref - https://github.com/cloudfoundry/multiapps-cli-plugin/blob/v3.2.2/util/archive_handler.go
-// GetMtaDescriptorFromArchive retrieves MTA ID from MTA archive.
+// GetMtaDescriptorFromArchive retrieves and validates the deployment descriptor of the MTA archive. Errors of the
+// descriptor itself are DescriptorErrors.
 func GetMtaDescriptorFromArchive(mtaArchiveFilePath string) (MtaDescriptor, error) {
 	mtaArchiveReader, err := zip.OpenReader(mtaArchiveFilePath)
 	if err != nil {
@@ -45,15 +94,7 @@ func GetMtaDescriptorFromArchive(mtaArchiveFilePath string) (MtaDescriptor, erro
 		return MtaDescriptor{}, err
 	}
 
-	var descriptor MtaDescriptor
-	if err = yaml.Unmarshal(descriptorBytes, &descriptor); err != nil {
-		return MtaDescriptor{}, err
-	}
-
-	if descriptor.ID != "" {
-		return descriptor, nil
-	}
-	return MtaDescriptor{}, errors.New("could not get a valid mta descriptor from archive")
+	return ParseMtaDescriptor(defaultDescriptorPath, descriptorBytes, false)
 }
 
 func findMtaDescriptorFile(files []*zip.File) *zip.File {
@@ -76,7 +117,7 @@ func readZipFile(file *zip.File) ([]byte, error) {
 
 // ref - https://github.com/cloudfoundry/multiapps-cli-plugin/blob/v3.2.2/commands/deploy_command.go
 // CheckOngoingOperation checks for ongoing operation for mta with the specified id and tries to abort it.
-func CheckOngoingOperation(ctx context.Context, client *APIClient, mtaId string, namespace string, spaceGuid string) (bool, error) {
+func CheckOngoingOperation(ctx context.Context, client *APIClient, mtaId string, namespace string, spaceGuid string, backoff Backoff) (bool, error) {
 	// Check if there is an ongoing operation for this MTA ID
 	ongoingOperation, err := findOngoingOperation(ctx, mtaId, namespace, client, spaceGuid)
 	if err != nil {
@@ -84,20 +125,67 @@ func CheckOngoingOperation(ctx context.Context, client *APIClient, mtaId string,
 	}
 	if ongoingOperation != nil {
 		// Abort the conflicting process
-		operationId, _, err := client.DefaultApi.ExecuteOperationAction(ctx, spaceGuid, ongoingOperation.ProcessId, "abort")
+		err = AbortMtaOperation(ctx, client, spaceGuid, ongoingOperation.ProcessId, backoff)
 		if err != nil {
 			return false, err
-		} else {
-			_, err = PollMtaOperation(ctx, client, spaceGuid, operationId, AbortedState)
-			if err != nil {
-				return false, err
-			}
 		}
 	}
 
 	return true, nil
 }
 
+// AbortMtaOperation aborts the MTA operation with the specified id and waits until it is aborted.
+func AbortMtaOperation(ctx context.Context, client *APIClient, spaceGuid string, operationId string, backoff Backoff) error {
+	abortOperationId, _, err := client.DefaultApi.ExecuteOperationAction(ctx, spaceGuid, operationId, "abort")
+	if err != nil {
+		return err
+	}
+	_, err = PollMtaOperation(ctx, client, spaceGuid, abortOperationId, AbortedState, backoff, nil)
+	return err
+}
+
+// RetryMtaOperation retries the failed MTA operation with the specified id and waits until the retry has started, i.e.
+// the operation left the ERROR state or reported new messages, so that polling it afterwards does not count the
+// failure of the previous attempt again.
+func RetryMtaOperation(ctx context.Context, client *APIClient, spaceGuid string, operationId string, backoff Backoff) error {
+	failed, _, err := client.DefaultApi.GetMtaOperation(ctx, spaceGuid, operationId, "messages")
+	if err != nil {
+		return err
+	}
+	seenMessageId := lastMessageId(failed.Messages)
+	if _, _, err = client.DefaultApi.ExecuteOperationAction(ctx, spaceGuid, operationId, "retry"); err != nil {
+		return err
+	}
+	for interval := backoff.Interval; ; interval = backoff.next(interval) {
+		if err = wait(ctx, interval); err != nil {
+			return fmt.Errorf("stopped waiting for operation %s to be retried: %w", operationId, err)
+		}
+		operation, _, err := client.DefaultApi.GetMtaOperation(ctx, spaceGuid, operationId, "messages")
+		if err != nil {
+			return err
+		}
+		if retryStarted(operation, seenMessageId) {
+			return nil
+		}
+	}
+}
+
+// retryStarted reports whether the retried operation left the ERROR state or reported messages after the seen one.
+func retryStarted(operation Operation, seenMessageId int64) bool {
+	return operation.State != "ERROR" || lastMessageId(operation.Messages) > seenMessageId
+}
+
+// lastMessageId returns the highest id of the messages, or 0 if there are none.
+func lastMessageId(messages []Message) int64 {
+	var last int64
+	for _, message := range messages {
+		if message.Id > last {
+			last = message.Id
+		}
+	}
+	return last
+}
+
 // FindOngoingOperation finds ongoing operation for mta with the specified id.
 func findOngoingOperation(ctx context.Context, mtaID string, namespace string, client *APIClient, spaceGuid string) (*Operation, error) {
 	activeStatesList := []string{"RUNNING", "ERROR", "ACTION_REQUIRED"}
@@ -125,25 +213,36 @@ func isConflicting(operation Operation, mtaID string, namespace string, spaceGui
 		operation.AcquiredLock
 }
 
-// Keeps polling the MTA operation by its ID for completion.
-func PollMtaOperation(ctx context.Context, client *APIClient, spaceGuid string, operationId string, targetState string) (string, error) {
+// Keeps polling the MTA operation by its ID for completion. Polling stops with an error wrapping the error of the
+// context once it is cancelled or its deadline is exceeded. If onMessage is set, it is called once for every message
+// of the operation as soon as the message is polled.
+func PollMtaOperation(ctx context.Context, client *APIClient, spaceGuid string, operationId string, targetState string, backoff Backoff, onMessage func(Message)) (string, error) {
 
 	var (
 		operationResponse Operation
 		err               error
 	)
-	for operationState := "RUNNING"; operationState != targetState; {
-		time.Sleep(2 * time.Second)
+	emitted := map[int64]bool{}
+	interval := backoff.Interval
+	for operationState := "RUNNING"; operationState != targetState; interval = backoff.next(interval) {
+		if err = wait(ctx, interval); err != nil {
+			return messagesToString(operationResponse.Messages), fmt.Errorf("stopped waiting for operation %s to reach state %s: %w", operationId, targetState, err)
+		}
 		operationResponse, _, err = client.DefaultApi.GetMtaOperation(ctx, spaceGuid, operationId, "messages")
 		if err != nil {
 			return "", err
 		}
+		if onMessage != nil {
+			for _, message := range newMessages(operationResponse.Messages, emitted) {
+				onMessage(message)
+			}
+		}
 		operationState = operationResponse.State
 		if operationState == "ERROR" {
 			if messageCount := len(operationResponse.Messages); messageCount > 0 {
-				return messagesToString(operationResponse.Messages), fmt.Errorf("last message %s", operationResponse.Messages[messageCount-1].Text)
+				return messagesToString(operationResponse.Messages), fmt.Errorf("%w, last message %s", ErrOperationFailed, operationResponse.Messages[messageCount-1].Text)
 			}
-			return "", fmt.Errorf("Operation failed with errorType %s", operationResponse.ErrorType)
+			return "", fmt.Errorf("%w with errorType %s", ErrOperationFailed, operationResponse.ErrorType)
 		}
 	}
 	return messagesToString(operationResponse.Messages), nil
@@ -177,10 +276,13 @@ func decodeOperationJobID(resp *http.Response) (string, error) {
 	return operationId, err
 }
 
-// Keeps polling the MTA job by its ID for completion.
-func PollMtaJob(ctx context.Context, client *APIClient, spaceGuid string, jobId string, targetState string, xInstance string, namespace string) (jobResponse UploadStatus, err error) {
-	for jobState := "RUNNING"; jobState != targetState; {
-		time.Sleep(2 * time.Second)
+// Keeps polling the MTA job by its ID for completion, until the context is cancelled or its deadline is exceeded.
+func PollMtaJob(ctx context.Context, client *APIClient, spaceGuid string, jobId string, targetState string, xInstance string, namespace string, backoff Backoff) (jobResponse UploadStatus, err error) {
+	interval := backoff.Interval
+	for jobState := "RUNNING"; jobState != targetState; interval = backoff.next(interval) {
+		if err = wait(ctx, interval); err != nil {
+			return jobResponse, fmt.Errorf("stopped waiting for job %s to reach state %s: %w", jobId, targetState, err)
+		}
 		jobResponse, _, err = client.DefaultApi.GetAsyncUploadJob(ctx, spaceGuid, jobId, xInstance, namespace)
 		if err != nil {
 			return jobResponse, err
@@ -193,6 +295,44 @@ func PollMtaJob(ctx context.Context, client *APIClient, spaceGuid string, jobId
 	return jobResponse, nil
 }
 
+// newMessages returns the messages which have not been emitted yet and marks them as emitted.
+func newMessages(messages []Message, emitted map[int64]bool) []Message {
+	var unseen []Message
+	for _, message := range messages {
+		if emitted[message.Id] {
+			continue
+		}
+		emitted[message.Id] = true
+		unseen = append(unseen, message)
+	}
+	return unseen
+}
+
+// GetMtaOperationLog downloads the logs of the MTA operation, e.g. the log of its process, and returns their last lines.
+func GetMtaOperationLog(ctx context.Context, client *APIClient, spaceGuid string, operationId string, lines int) (string, error) {
+	logs, _, err := client.DefaultApi.GetMtaOperationLogs(ctx, spaceGuid, operationId)
+	if err != nil {
+		return "", fmt.Errorf("could not list the logs of operation %s: %w", operationId, err)
+	}
+	var content []string
+	for _, log := range logs {
+		logContent, _, err := client.DefaultApi.GetMtaOperationLogContent(ctx, spaceGuid, operationId, log.Id)
+		if err != nil {
+			return "", fmt.Errorf("could not download log %s of operation %s: %w", log.Id, operationId, err)
+		}
+		content = append(content, strings.Split(strings.TrimRight(logContent, "\n"), "\n")...)
+	}
+	return strings.Join(lastLines(content, lines), "\n"), nil
+}
+
+// lastLines returns at most the last n lines.
+func lastLines(lines []string, n int) []string {
+	if len(lines) > n {
+		return lines[len(lines)-n:]
+	}
+	return lines
+}
+
 // Combines multiple messages into a single string.
 func messagesToString(messages []Message) (combinedMessage string) {
 	for _, message := range messages {
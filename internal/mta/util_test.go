@@ -0,0 +1,74 @@
+package mta
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffNext(t *testing.T) {
+	backoff := Backoff{Interval: 2 * time.Second, MaxInterval: 10 * time.Second, Factor: 2}
+	tests := []struct {
+		interval time.Duration
+		expected time.Duration
+	}{
+		{2 * time.Second, 4 * time.Second},
+		{4 * time.Second, 8 * time.Second},
+		{8 * time.Second, 10 * time.Second},
+		{10 * time.Second, 10 * time.Second},
+		{0, 2 * time.Second},
+	}
+	for _, test := range tests {
+		if next := backoff.next(test.interval); next != test.expected {
+			t.Errorf("next(%s) = %s, expected %s", test.interval, next, test.expected)
+		}
+	}
+}
+
+func TestWaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := wait(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if err := wait(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestNewMessages(t *testing.T) {
+	emitted := map[int64]bool{}
+	first := newMessages([]Message{{Id: 1, Text: "Uploading"}, {Id: 2, Text: "Deploying"}}, emitted)
+	if len(first) != 2 {
+		t.Fatalf("expected 2 new messages, got %d", len(first))
+	}
+	second := newMessages([]Message{{Id: 1, Text: "Uploading"}, {Id: 2, Text: "Deploying"}, {Id: 3, Text: "Finished"}}, emitted)
+	if len(second) != 1 || second[0].Text != "Finished" {
+		t.Errorf("expected only the message Finished, got %v", second)
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	lines := []string{"a", "b", "c"}
+	if got := lastLines(lines, 2); len(got) != 2 || got[0] != "b" {
+		t.Errorf("expected [b c], got %v", got)
+	}
+	if got := lastLines(lines, 5); len(got) != 3 {
+		t.Errorf("expected all lines, got %v", got)
+	}
+}
+
+func TestRetryStarted(t *testing.T) {
+	failed := Operation{State: "ERROR", Messages: []Message{{Id: 3, Text: "Deploying"}, {Id: 7, Text: "Service broker error"}}}
+	if retryStarted(failed, lastMessageId(failed.Messages)) {
+		t.Errorf("expected the stale ERROR state not to count as a started retry")
+	}
+	if !retryStarted(Operation{State: "RUNNING", Messages: failed.Messages}, 7) {
+		t.Errorf("expected a RUNNING operation to count as a started retry")
+	}
+	failedAgain := Operation{State: "ERROR", Messages: append(failed.Messages, Message{Id: 8, Text: "Service broker error"})}
+	if !retryStarted(failedAgain, 7) {
+		t.Errorf("expected a new message to count as a started retry")
+	}
+}
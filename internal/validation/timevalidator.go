@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var (
+	_ validator.String = timeValidator{}
+)
+
+type timeValidator struct {
+	description string
+	parse       func(string) error
+}
+
+func (v timeValidator) Description(ctx context.Context) string {
+	return v.description
+}
+
+func (v timeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v timeValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := v.parse(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %s", req.Path, v.description, req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// ValidRFC3339 checks that the String held in the attribute is a timestamp in RFC3339 format.
+func ValidRFC3339() validator.String {
+	return timeValidator{
+		description: "value must be a timestamp in RFC3339 format, e.g. 2024-05-01T10:00:00Z",
+		parse: func(value string) error {
+			_, err := time.Parse(time.RFC3339, value)
+			return err
+		},
+	}
+}
+
+// ValidDuration checks that the String held in the attribute is a positive duration, e.g. 15m or 1h30m.
+func ValidDuration() validator.String {
+	return timeValidator{
+		description: "value must be a positive duration, e.g. 15m or 1h30m",
+		parse: func(value string) error {
+			d, err := time.ParseDuration(value)
+			if err == nil && d <= 0 {
+				err = fmt.Errorf("duration %s is not positive", value)
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTimeValidators(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		validator validator.String
+		in        types.String
+		expErrors int
+	}
+
+	testCases := map[string]testCase{
+		"rfc3339-match": {
+			validator: ValidRFC3339(),
+			in:        types.StringValue("2024-05-01T10:00:00Z"),
+			expErrors: 0,
+		},
+		"rfc3339-match-offset": {
+			validator: ValidRFC3339(),
+			in:        types.StringValue("2024-05-01T12:00:00+02:00"),
+			expErrors: 0,
+		},
+		"rfc3339-mismatch": {
+			validator: ValidRFC3339(),
+			in:        types.StringValue("2024-05-01 10:00"),
+			expErrors: 1,
+		},
+		"rfc3339-skip-validation-on-null": {
+			validator: ValidRFC3339(),
+			in:        types.StringNull(),
+			expErrors: 0,
+		},
+		"duration-match": {
+			validator: ValidDuration(),
+			in:        types.StringValue("1h30m"),
+			expErrors: 0,
+		},
+		"duration-mismatch": {
+			validator: ValidDuration(),
+			in:        types.StringValue("15 minutes"),
+			expErrors: 1,
+		},
+		"duration-negative": {
+			validator: ValidDuration(),
+			in:        types.StringValue("-5m"),
+			expErrors: 1,
+		},
+		"duration-skip-validation-on-unknown": {
+			validator: ValidDuration(),
+			in:        types.StringUnknown(),
+			expErrors: 0,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := validator.StringRequest{
+				ConfigValue: test.in,
+			}
+			res := validator.StringResponse{}
+			test.validator.ValidateString(context.TODO(), req, &res)
+
+			if test.expErrors > 0 && !res.Diagnostics.HasError() {
+				t.Fatalf("expected %d error(s), got none", test.expErrors)
+			}
+
+			if test.expErrors > 0 && test.expErrors != res.Diagnostics.ErrorsCount() {
+				t.Fatalf("expected %d error(s), got %d: %v", test.expErrors, res.Diagnostics.ErrorsCount(), res.Diagnostics)
+			}
+
+			if test.expErrors == 0 && res.Diagnostics.HasError() {
+				t.Fatalf("expected no error(s), got %d: %v", res.Diagnostics.ErrorsCount(), res.Diagnostics)
+			}
+		})
+	}
+}